@@ -0,0 +1,471 @@
+// Package client is a typed Go client for the recipe finder HTTP API described
+// in docs/openapi.json. It only depends on the standard library, so services can
+// use it without pulling in the SQLite driver.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Client calls the API of one backend
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient makes the client send requests with httpClient instead of
+// http.DefaultClient, for example to set a timeout
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// New creates a client for the backend at baseURL, for example "http://localhost:8081"
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: http.DefaultClient,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Error is a response whose envelope status is "error"
+type Error struct {
+	StatusCode int          // HTTP status code
+	Code       string       // Machine-readable code, for example ELEMENT_NOT_FOUND
+	Message    string       // Human-readable message
+	Errors     []FieldError // Rejected fields, for VALIDATION_FAILED and UNKNOWN_ELEMENTS
+	RequestID  string
+	Data       json.RawMessage // Payload sent with the error, if any
+}
+
+func (e *Error) Error() string {
+	message := fmt.Sprintf("%s (%d %s)", e.Message, e.StatusCode, e.Code)
+	for _, field := range e.Errors {
+		message += fmt.Sprintf("; %s %s", field.Field, field.Message)
+	}
+	return message
+}
+
+// DidYouMean returns the names suggested with an ELEMENT_NOT_FOUND search error
+func (e *Error) DidYouMean() []Suggestion {
+	var data SearchResult
+	if len(e.Data) == 0 || json.Unmarshal(e.Data, &data) != nil {
+		return nil
+	}
+	return data.DidYouMean
+}
+
+// envelope is the wrapper of every response
+type envelope struct {
+	Status    string          `json:"status"`
+	Code      string          `json:"code"`
+	Message   string          `json:"message"`
+	Errors    []FieldError    `json:"errors"`
+	RequestID string          `json:"requestId"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// newRequest builds a request with a JSON body when body is not nil
+func (c *Client) newRequest(ctx context.Context, method, path string, query url.Values, body any) (*http.Request, error) {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(encoded)
+	}
+	target := c.baseURL + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// do sends a request and decodes the data of a successful response into out.
+// The response code is returned so callers can tell apart successful outcomes.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) (string, error) {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	return decodeEnvelope(resp, out)
+}
+
+// decodeEnvelope reads an enveloped response, see do
+func decodeEnvelope(resp *http.Response, out any) (string, error) {
+	method, path := resp.Request.Method, resp.Request.URL.Path
+	var env envelope
+	if err := json.NewDecoder(resp.Body).Decode(&env); err != nil {
+		return "", fmt.Errorf("%s %s: %d %s: %w", method, path, resp.StatusCode, http.StatusText(resp.StatusCode), err)
+	}
+	if env.Status != "success" {
+		return env.Code, &Error{
+			StatusCode: resp.StatusCode,
+			Code:       env.Code,
+			Message:    env.Message,
+			Errors:     env.Errors,
+			RequestID:  env.RequestID,
+			Data:       env.Data,
+		}
+	}
+	if out != nil && len(env.Data) > 0 {
+		if err := json.Unmarshal(env.Data, out); err != nil {
+			return env.Code, fmt.Errorf("%s %s: %w", method, path, err)
+		}
+	}
+	return env.Code, nil
+}
+
+// stream sends a request asking for NDJSON and calls line with the type field and
+// the raw JSON of every line. When the server answers with an envelope instead, for
+// errors and results that have nothing to stream, it is decoded into out like do
+// and its code is returned; the code is empty for a stream.
+func (c *Client) stream(ctx context.Context, method, path string, query url.Values, body, out any, line func(kind string, raw json.RawMessage) error) (string, error) {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/x-ndjson")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/x-ndjson") {
+		return decodeEnvelope(resp, out)
+	}
+
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var raw json.RawMessage
+		if err := decoder.Decode(&raw); err == io.EOF {
+			return "", nil
+		} else if err != nil {
+			return "", fmt.Errorf("%s %s: %w", method, path, err)
+		}
+		var probe struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal(raw, &probe); err != nil {
+			return "", fmt.Errorf("%s %s: %w", method, path, err)
+		}
+		if err := line(probe.Type, raw); err != nil {
+			return "", err
+		}
+	}
+}
+
+// Search finds recipes for an element. Unknown elements and searches without a
+// complete recipe return an *Error with code ELEMENT_NOT_FOUND or NO_RECIPE.
+func (c *Client) Search(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	return c.search(ctx, req, false)
+}
+
+// SearchStrict is Search with the strict query parameter, so the server rejects
+// fields it does not know
+func (c *Client) SearchStrict(ctx context.Context, req SearchRequest) (*SearchResult, error) {
+	return c.search(ctx, req, true)
+}
+
+func (c *Client) search(ctx context.Context, req SearchRequest, strict bool) (*SearchResult, error) {
+	var query url.Values
+	if strict {
+		query = url.Values{"strict": {"true"}}
+	}
+	result := &SearchResult{}
+	code, err := c.do(ctx, http.MethodPost, "/api/search", query, req, result)
+	if err != nil {
+		return nil, err
+	}
+	result.Status = SearchStatus(code)
+	return result, nil
+}
+
+// SearchStream runs a search and calls fn with every recipe tree as soon as the
+// server finds it, so "All" searches with many recipes need neither a large response
+// nor a long wait. Trees are not ranked by new combinations. Searches without a
+// recipe return the same errors as Search, and basic elements a summary with no
+// recipes. An error from fn stops reading the stream.
+func (c *Client) SearchStream(ctx context.Context, req SearchRequest, fn func(*RecipeTree) error) (*SearchSummary, error) {
+	var summary *SearchSummary
+	var result SearchResult
+	code, err := c.stream(ctx, http.MethodPost, "/api/search", nil, req, &result, func(kind string, raw json.RawMessage) error {
+		switch kind {
+		case "recipe":
+			tree := &RecipeTree{}
+			if err := json.Unmarshal(raw, tree); err != nil {
+				return err
+			}
+			return fn(tree)
+		case "summary":
+			summary = &SearchSummary{}
+			return json.Unmarshal(raw, summary)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if code != "" {
+		// Nothing to stream, for example a basic element
+		return &SearchSummary{Status: SearchStatus(code), NodesVisited: result.NodesVisited, ExecutionTime: result.ExecutionTime, TraceID: result.TraceID}, nil
+	}
+	if summary == nil {
+		return nil, fmt.Errorf("POST /api/search: stream ended without a summary")
+	}
+	return summary, nil
+}
+
+// SearchURL returns the GET /api/search URL of a search, which can be bookmarked,
+// shared and cached by browsers and proxies
+func (c *Client) SearchURL(req SearchRequest) string {
+	query := url.Values{
+		"element":   {req.ElementName},
+		"algorithm": {req.Algorithm},
+		"type":      {req.RecipeType},
+	}
+	if req.MaxRecipes > 0 {
+		query.Set("max", strconv.Itoa(req.MaxRecipes))
+	}
+	if req.Dataset != "" {
+		query.Set("dataset", req.Dataset)
+	}
+	if req.Player != "" {
+		query.Set("player", req.Player)
+	}
+	if req.Trace {
+		query.Set("trace", "true")
+	}
+	for key, values := range map[string][]string{"owned": req.Owned, "exclude": req.Exclude, "require": req.Require} {
+		for _, value := range values {
+			query.Add(key, value)
+		}
+	}
+	return c.baseURL + "/api/search?" + query.Encode()
+}
+
+// SearchSocketURL returns the ws:// or wss:// URL of the WebSocket that streams the
+// steps of a search. The client stays free of dependencies and does not speak the
+// WebSocket protocol itself; dial the URL with any WebSocket library, send a start
+// message and decode the step events into Step.
+func (c *Client) SearchSocketURL() string {
+	base := c.baseURL
+	if rest, ok := strings.CutPrefix(base, "https://"); ok {
+		base = "wss://" + rest
+	} else if rest, ok := strings.CutPrefix(base, "http://"); ok {
+		base = "ws://" + rest
+	}
+	return base + "/api/ws/search"
+}
+
+// SearchBatch runs several searches in one request. Failed searches do not fail
+// the batch; each item carries its own status and code.
+func (c *Client) SearchBatch(ctx context.Context, req BatchRequest) (*BatchResult, error) {
+	result := &BatchResult{}
+	if _, err := c.do(ctx, http.MethodPost, "/api/search/batch", nil, req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// SearchBatchStream runs a batch and calls fn with every item as soon as its search
+// finishes, in completion order. An error from fn stops reading the stream.
+func (c *Client) SearchBatchStream(ctx context.Context, req BatchRequest, fn func(BatchItem) error) (*BatchSummary, error) {
+	var summary *BatchSummary
+	_, err := c.stream(ctx, http.MethodPost, "/api/search/batch", nil, req, nil, func(kind string, raw json.RawMessage) error {
+		switch kind {
+		case "result":
+			var item BatchItem
+			if err := json.Unmarshal(raw, &item); err != nil {
+				return err
+			}
+			return fn(item)
+		case "summary":
+			summary = &BatchSummary{}
+			return json.Unmarshal(raw, summary)
+		}
+		return nil
+	})
+	if err == nil && summary == nil {
+		err = fmt.Errorf("POST /api/search/batch: stream ended without a summary")
+	}
+	return summary, err
+}
+
+// Trace returns a trace recorded by a search with SearchRequest.Trace, with every
+// stored step. Traces are kept in memory by the backend and dropped for newer ones,
+// which returns an *Error with code TRACE_NOT_FOUND.
+func (c *Client) Trace(ctx context.Context, id string) (*Trace, error) {
+	trace := &Trace{}
+	if _, err := c.do(ctx, http.MethodGet, tracePath(id), nil, nil, trace); err != nil {
+		return nil, err
+	}
+	return trace, nil
+}
+
+// ReplayTrace streams the steps of a trace and calls fn with each one, so large
+// traces are not held in memory. Only steps of the given kinds are sent, every kind
+// when kinds is empty. An error from fn stops reading the stream.
+func (c *Client) ReplayTrace(ctx context.Context, id string, kinds []string, fn func(Step) error) (*TraceSummary, error) {
+	var query url.Values
+	if len(kinds) > 0 {
+		query = url.Values{"kinds": {strings.Join(kinds, ",")}}
+	}
+	var summary *TraceSummary
+	_, err := c.stream(ctx, http.MethodGet, tracePath(id), query, nil, nil, func(kind string, raw json.RawMessage) error {
+		switch kind {
+		case "step":
+			var step Step
+			if err := json.Unmarshal(raw, &step); err != nil {
+				return err
+			}
+			return fn(step)
+		case "summary":
+			summary = &TraceSummary{}
+			return json.Unmarshal(raw, summary)
+		}
+		return nil
+	})
+	if err == nil && summary == nil {
+		err = fmt.Errorf("GET %s: stream ended without a summary", tracePath(id))
+	}
+	return summary, err
+}
+
+func tracePath(id string) string {
+	return "/api/traces/" + url.PathEscape(id)
+}
+
+// Datasets lists the datasets the backend can search
+func (c *Client) Datasets(ctx context.Context) (*Datasets, error) {
+	datasets := &Datasets{}
+	if _, err := c.do(ctx, http.MethodGet, "/api/datasets", nil, nil, datasets); err != nil {
+		return nil, err
+	}
+	return datasets, nil
+}
+
+// SuggestElements autocompletes an element name. A limit of 0 uses the server
+// default and an empty dataset the default dataset.
+func (c *Client) SuggestElements(ctx context.Context, q string, limit int, dataset string) ([]Suggestion, error) {
+	query := url.Values{"q": {q}}
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if dataset != "" {
+		query.Set("dataset", dataset)
+	}
+	var data struct {
+		Suggestions []Suggestion `json:"suggestions"`
+	}
+	if _, err := c.do(ctx, http.MethodGet, "/api/elements/suggest", query, nil, &data); err != nil {
+		return nil, err
+	}
+	return data.Suggestions, nil
+}
+
+// FullPlan returns the combination order that unlocks every reachable element
+func (c *Client) FullPlan(ctx context.Context, dataset string) (*FullPlan, error) {
+	var query url.Values
+	if dataset != "" {
+		query = url.Values{"dataset": {dataset}}
+	}
+	plan := &FullPlan{}
+	if _, err := c.do(ctx, http.MethodGet, "/api/plan/full", query, nil, plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Hints suggests the next combinations to try, best first
+func (c *Client) Hints(ctx context.Context, req HintRequest) ([]Hint, error) {
+	var data struct {
+		Hints []Hint `json:"hints"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, "/api/hints", nil, req, &data); err != nil {
+		return nil, err
+	}
+	return data.Hints, nil
+}
+
+// Player returns a player profile with every discovery
+func (c *Client) Player(ctx context.Context, id string) (*Profile, error) {
+	profile := &Profile{}
+	if _, err := c.do(ctx, http.MethodGet, playerPath(id, ""), nil, nil, profile); err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// ImportPlayer adds discovered elements to a player profile, creating it on
+// dataset when missing, and returns the elements that were new
+func (c *Client) ImportPlayer(ctx context.Context, id, dataset string, discovered []string) ([]Discovery, error) {
+	req := struct {
+		Discovered []string `json:"discovered"`
+		Dataset    string   `json:"dataset,omitempty"`
+	}{discovered, dataset}
+	var data struct {
+		Added []Discovery `json:"added"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, playerPath(id, "/import"), nil, req, &data); err != nil {
+		return nil, err
+	}
+	return data.Added, nil
+}
+
+// Discover combines two discovered elements of a player
+func (c *Client) Discover(ctx context.Context, id, item1, item2 string) (*CraftResult, error) {
+	req := struct {
+		Item1 string `json:"item1"`
+		Item2 string `json:"item2"`
+	}{item1, item2}
+	result := &CraftResult{}
+	if _, err := c.do(ctx, http.MethodPost, playerPath(id, "/discoveries"), nil, req, result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// Progress returns a player's completion overall and per tier
+func (c *Client) Progress(ctx context.Context, id string) (*Progress, error) {
+	progress := &Progress{}
+	if _, err := c.do(ctx, http.MethodGet, playerPath(id, "/progress"), nil, nil, progress); err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
+
+func playerPath(id, suffix string) string {
+	return "/api/players/" + url.PathEscape(id) + suffix
+}
@@ -0,0 +1,264 @@
+package client
+
+import "time"
+
+// Algorithms accepted by SearchRequest.Algorithm
+const (
+	BFS           = "BFS"
+	DFS           = "DFS"
+	Bidirectional = "Bidirectional"
+)
+
+// Recipe types accepted by SearchRequest.RecipeType
+const (
+	RecipeOne   = "One"   // Stop at the first recipe
+	RecipeLimit = "Limit" // Stop after MaxRecipes recipes
+	RecipeAll   = "All"   // Every recipe
+)
+
+// SearchStatus is the code of a successful search response
+type SearchStatus string
+
+// Search statuses returned without an error. ELEMENT_NOT_FOUND and NO_RECIPE
+// come back as an *Error.
+const (
+	StatusOK           SearchStatus = "OK"
+	StatusLimitReached SearchStatus = "LIMIT_REACHED"
+	StatusBasicElement SearchStatus = "BASIC_ELEMENT"
+)
+
+// FieldError is one rejected request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// SearchRequest is the body of POST /api/search
+type SearchRequest struct {
+	ElementName string   `json:"elementName"`
+	Algorithm   string   `json:"algorithm"`
+	RecipeType  string   `json:"recipeType"`
+	MaxRecipes  int      `json:"maxRecipes,omitempty"` // Required for RecipeLimit, at most 1000
+	Dataset     string   `json:"dataset,omitempty"`
+	Owned       []string `json:"owned,omitempty"`
+	Exclude     []string `json:"exclude,omitempty"` // Elements or "Item1 + Item2" combinations
+	Require     []string `json:"require,omitempty"`
+	Player      string   `json:"player,omitempty"`
+	Trace       bool     `json:"trace,omitempty"` // Record every step, see Client.Trace
+}
+
+// SearchResult is the data of a search response
+type SearchResult struct {
+	Status        SearchStatus  `json:"-"`
+	Results       []*RecipeTree `json:"results"`
+	NodesVisited  int           `json:"nodesVisited"`
+	ExecutionTime float64       `json:"executionTime"` // Milliseconds
+	Element       string        `json:"element,omitempty"`
+	DidYouMean    []Suggestion  `json:"didYouMean,omitempty"`
+	TraceID       string        `json:"traceId,omitempty"` // Only with SearchRequest.Trace
+}
+
+// SearchSummary is the last line of a streamed search
+type SearchSummary struct {
+	Status        SearchStatus `json:"code"`
+	Message       string       `json:"message"`
+	Recipes       int          `json:"recipes"` // Number of trees streamed
+	NodesVisited  int          `json:"nodesVisited"`
+	ExecutionTime float64      `json:"executionTime"` // Milliseconds
+	TraceID       string       `json:"traceId,omitempty"`
+}
+
+// Kinds of Step
+const (
+	StepVisit  = "visit"  // A node was dequeued (BFS, Bidirectional) or popped (DFS)
+	StepCycle  = "cycle"  // The node was skipped because it is already on its own path
+	StepExpand = "expand" // A combination of the node was expanded into its ingredients
+	StepPush   = "push"   // An ingredient was queued (BFS, Bidirectional) or pushed (DFS)
+	StepDedup  = "dedup"  // A complete recipe was dropped because it was already found
+	StepRecipe = "recipe" // A complete recipe was found
+)
+
+// Step is one event of a traversal, as sent by the search WebSocket and stored in
+// a Trace
+type Step struct {
+	Seq          int      `json:"seq"` // Numbers the steps of one search from 1
+	Kind         string   `json:"kind"`
+	Element      string   `json:"element"`
+	Item1        string   `json:"item1,omitempty"` // Ingredients, for StepExpand
+	Item2        string   `json:"item2,omitempty"`
+	Depth        int      `json:"depth"`
+	NodesVisited int      `json:"nodesVisited"`
+	Recipe       []string `json:"recipe,omitempty"` // For StepRecipe and StepDedup
+}
+
+// TraceSummary is the request and outcome of a traced search
+type TraceSummary struct {
+	ID           string         `json:"id"`
+	Dataset      string         `json:"dataset"`
+	CreatedAt    time.Time      `json:"createdAt"`
+	Algorithm    string         `json:"algorithm"`
+	Element      string         `json:"element"`
+	RecipeType   string         `json:"recipeType"`
+	MaxRecipes   int            `json:"maxRecipes,omitempty"`
+	Status       SearchStatus   `json:"status"`
+	NodesVisited int            `json:"nodesVisited"`
+	Recipes      int            `json:"recipes"`
+	Events       int            `json:"events"`          // Every step, stored or not
+	Truncated    bool           `json:"truncated"`       // Steps does not hold every step
+	Error        string         `json:"error,omitempty"` // Why a step could not be stored
+	Counts       map[string]int `json:"counts"`          // Steps of each kind, stored or not
+}
+
+// Trace is a recorded traversal
+type Trace struct {
+	TraceSummary
+	Steps []Step `json:"steps"`
+}
+
+// BatchRequest is the body of POST /api/search/batch
+type BatchRequest struct {
+	Searches    []SearchRequest `json:"searches"`              // 1 to 100 searches
+	Concurrency int             `json:"concurrency,omitempty"` // Searches running at once, at most 8
+	BudgetMs    int             `json:"budgetMs,omitempty"`    // Time for the whole batch, at most 60000
+}
+
+// BatchItem is the outcome of one search of a batch. Status, Code and Message mean
+// the same as in the response of a single search.
+type BatchItem struct {
+	Index   int           `json:"index"` // Position in BatchRequest.Searches
+	Element string        `json:"element"`
+	Status  string        `json:"status"`
+	Code    string        `json:"code"` // BUDGET_EXHAUSTED when the batch ran out of time before the search finished
+	Message string        `json:"message"`
+	Errors  []FieldError  `json:"errors,omitempty"`
+	Data    *SearchResult `json:"data,omitempty"`
+}
+
+// BatchSummary counts the outcomes of a batch
+type BatchSummary struct {
+	Total        int     `json:"total"`
+	Succeeded    int     `json:"succeeded"`
+	Failed       int     `json:"failed"`
+	Skipped      int     `json:"skipped"` // Not started before the budget ran out
+	CutOff       int     `json:"cutOff"`  // Stopped while running when the budget ran out
+	NodesVisited int     `json:"nodesVisited"`
+	ElapsedMs    float64 `json:"elapsedMs"`
+}
+
+// BatchResult is the data of a batch response, items in request order
+type BatchResult struct {
+	Results []BatchItem  `json:"results"`
+	Summary BatchSummary `json:"summary"`
+}
+
+// RecipeTree is one recipe as a tree of ingredients. Only the root carries Recipe.
+type RecipeTree struct {
+	Name            string        `json:"name"`
+	Image           string        `json:"image"`
+	Children        []*RecipeTree `json:"children"`
+	Recipe          []string      `json:"recipe,omitempty"`
+	Owned           bool          `json:"owned,omitempty"`
+	NewCombinations int           `json:"newCombinations,omitempty"`
+}
+
+// Datasets lists the datasets of the backend
+type Datasets struct {
+	Datasets []string `json:"datasets"`
+	Default  string   `json:"default"`
+}
+
+// Suggestion is an element name close to a query
+type Suggestion struct {
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	Prefix   bool   `json:"prefix"`
+	Distance int    `json:"distance"`
+}
+
+// FullPlan is the combination order that unlocks every reachable element
+type FullPlan struct {
+	Dataset     string           `json:"dataset"`
+	TotalSteps  int              `json:"totalSteps"`
+	Basic       int              `json:"basic"`
+	Discovered  int              `json:"discovered"`
+	Unreachable []string         `json:"unreachable"`
+	Checkpoints []TierCheckpoint `json:"checkpoints"`
+	Steps       []FullPlanStep   `json:"steps"`
+}
+
+// FullPlanStep is one combination of a full plan
+type FullPlanStep struct {
+	Item1     string   `json:"item1"`
+	Item2     string   `json:"item2"`
+	Discovers []string `json:"discovers"`
+	Tier      int      `json:"tier"`
+}
+
+// TierCheckpoint summarises a full plan once a tier is complete
+type TierCheckpoint struct {
+	Tier          int `json:"tier"`
+	Steps         int `json:"steps"`
+	TotalSteps    int `json:"totalSteps"`
+	Elements      int `json:"elements"`
+	TotalElements int `json:"totalElements"`
+}
+
+// HintRequest is the body of POST /api/hints
+type HintRequest struct {
+	Discovered []string `json:"discovered,omitempty"`
+	Goal       string   `json:"goal,omitempty"`
+	Limit      int      `json:"limit,omitempty"`
+	Dataset    string   `json:"dataset,omitempty"`
+	Player     string   `json:"player,omitempty"`
+}
+
+// Hint is a suggested combination
+type Hint struct {
+	Item1     string   `json:"item1"`
+	Item2     string   `json:"item2"`
+	Results   []string `json:"results"`
+	Score     float64  `json:"score"`
+	Unlocks   int      `json:"unlocks"`
+	GoalSteps int      `json:"goalSteps"` // -1 without a goal
+	Tier      int      `json:"tier"`
+	Reasons   []string `json:"reasons"`
+}
+
+// Discovery is one element a player has found
+type Discovery struct {
+	Element      string    `json:"element"`
+	DiscoveredAt time.Time `json:"discoveredAt"`
+	Item1        string    `json:"item1,omitempty"`
+	Item2        string    `json:"item2,omitempty"`
+}
+
+// Profile is the saved progress of a player
+type Profile struct {
+	ID          string      `json:"id"`
+	Dataset     string      `json:"dataset"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	Discoveries []Discovery `json:"discoveries"`
+}
+
+// CraftResult is the outcome of combining two elements of a player
+type CraftResult struct {
+	Results []string    `json:"results"` // Every element the combination creates
+	Added   []Discovery `json:"added"`   // The results the player did not have yet
+}
+
+// Progress is a player's completion
+type Progress struct {
+	Dataset    string         `json:"dataset"`
+	Total      int            `json:"total"`
+	Discovered int            `json:"discovered"`
+	Percent    float64        `json:"percent"`
+	Tiers      []TierProgress `json:"tiers"`
+}
+
+// TierProgress is the completion of one tier
+type TierProgress struct {
+	Tier       int     `json:"tier"`
+	Total      int     `json:"total"`
+	Discovered int     `json:"discovered"`
+	Percent    float64 `json:"percent"`
+}
@@ -0,0 +1,99 @@
+// File ini adalah command untuk membandingkan dua versi dataset Little Alchemy 2.
+// Dipakai untuk meninjau perubahan hasil scrape sebelum dataset baru di-deploy.
+//
+// Penggunaan:
+//
+//	go run ./cmd/alchemy-diff [-format markdown|json] [-o file] lama.db baru.db
+//
+// Dataset boleh berupa file SQLite (.db), CSV (.csv) atau ekspor JSON (.json).
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"main/services"
+)
+
+// Kode keluar program
+const (
+	exitOK    = 0 // Berhasil
+	exitError = 1 // Dataset gagal dibuka atau laporan gagal ditulis
+	exitUsage = 2 // Argumen salah
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run menjalankan command dan mengembalikan kode keluar
+func run(args []string, stdout, errOut io.Writer) int {
+	fs := flag.NewFlagSet("alchemy-diff", flag.ContinueOnError)
+	fs.SetOutput(errOut)
+	format := fs.String("format", "markdown", "format laporan: markdown atau json")
+	output := fs.String("o", "", "file laporan (default: stdout)")
+	oldMapper := fs.String("old-mapper", "", "mapper ikon untuk dataset lama (opsional)")
+	newMapper := fs.String("new-mapper", "", "mapper ikon untuk dataset baru (opsional)")
+	dataset := fs.String("dataset", services.DefaultDataset, "nama dataset yang dibandingkan")
+	fs.Usage = func() {
+		fmt.Fprintf(errOut, "Penggunaan: alchemy-diff [flag] <dataset-lama> <dataset-baru>\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return exitUsage
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitUsage
+	}
+	if *format != "markdown" && *format != "json" {
+		fmt.Fprintf(errOut, "Format tidak dikenal: %q\n", *format)
+		return exitUsage
+	}
+
+	oldRepo, err := services.OpenRepository(fs.Arg(0), *oldMapper, *dataset)
+	if err != nil {
+		fmt.Fprintf(errOut, "Gagal membuka %s: %v\n", fs.Arg(0), err)
+		return exitError
+	}
+	newRepo, err := services.OpenRepository(fs.Arg(1), *newMapper, *dataset)
+	if err != nil {
+		fmt.Fprintf(errOut, "Gagal membuka %s: %v\n", fs.Arg(1), err)
+		return exitError
+	}
+
+	report, err := compare(oldRepo, newRepo)
+	if err != nil {
+		fmt.Fprintf(errOut, "Gagal membandingkan dataset: %v\n", err)
+		return exitError
+	}
+	report.Old, report.New = fs.Arg(0), fs.Arg(1)
+
+	out := stdout
+	if *output != "" {
+		file, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintf(errOut, "Gagal membuat %s: %v\n", *output, err)
+			return exitError
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		err = encoder.Encode(report)
+	} else {
+		err = writeMarkdown(out, report)
+	}
+	if err != nil {
+		fmt.Fprintf(errOut, "Gagal menulis laporan: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
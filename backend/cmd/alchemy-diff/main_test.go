@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeDataset menulis dataset CSV sementara dan mengembalikan path-nya
+func writeDataset(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("menulis %s: %v", path, err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	oldPath := writeDataset(t, "lama.csv", "Element,Item1,Item2\nWater,,\nFire,,\nSteam,Water,Fire\n")
+	newPath := writeDataset(t, "baru.csv", "Element,Item1,Item2\nWater,,\nFire,,\nSteam,Water,Fire\nAir,,\n")
+	missing := filepath.Join(t.TempDir(), "tidak-ada.csv")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{name: "tanpa argumen", args: nil, wantCode: exitUsage, wantErrOut: "Penggunaan: alchemy-diff"},
+		{name: "satu dataset", args: []string{oldPath}, wantCode: exitUsage, wantErrOut: "Penggunaan: alchemy-diff"},
+		{name: "flag tidak dikenal", args: []string{"-x", oldPath, newPath}, wantCode: exitUsage, wantErrOut: "-x"},
+		{name: "format tidak dikenal", args: []string{"-format", "yaml", oldPath, newPath}, wantCode: exitUsage, wantErrOut: `Format tidak dikenal: "yaml"`},
+		{name: "dataset tidak ada", args: []string{missing, newPath}, wantCode: exitError, wantErrOut: "Gagal membuka " + missing},
+		{name: "dataset tidak dikenal", args: []string{"-dataset", "myths", oldPath, newPath}, wantCode: exitError, wantErrOut: `dataset "myths" tidak ada`},
+		{name: "markdown", args: []string{oldPath, newPath}, wantCode: exitOK, wantOut: "## Elemen ditambah\n\n- Air\n"},
+		{name: "dataset sama", args: []string{oldPath, oldPath}, wantCode: exitOK, wantOut: "Tidak ada perubahan."},
+		{name: "json", args: []string{"-format", "json", oldPath, newPath}, wantCode: exitOK, wantOut: `"elementsAdded": [`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut strings.Builder
+			code := run(tt.args, &out, &errOut)
+			if code != tt.wantCode {
+				t.Fatalf("kode keluar = %d, ingin %d (stderr: %s)", code, tt.wantCode, errOut.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("stdout tidak berisi %q:\n%s", tt.wantOut, out.String())
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr tidak berisi %q:\n%s", tt.wantErrOut, errOut.String())
+			}
+		})
+	}
+}
+
+func TestRunOutputFile(t *testing.T) {
+	oldPath := writeDataset(t, "lama.csv", "Water,,\nFire,,\n")
+	newPath := writeDataset(t, "baru.csv", "Water,,\nFire,,\nSteam,Water,Fire\n")
+	output := filepath.Join(t.TempDir(), "laporan.json")
+
+	var out, errOut strings.Builder
+	if code := run([]string{"-format", "json", "-o", output, oldPath, newPath}, &out, &errOut); code != exitOK {
+		t.Fatalf("kode keluar = %d (stderr: %s)", code, errOut.String())
+	}
+	if out.Len() != 0 {
+		t.Errorf("stdout harus kosong jika -o dipakai, dapat %q", out.String())
+	}
+
+	data, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatalf("membaca laporan: %v", err)
+	}
+	var report Report
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("laporan bukan JSON yang valid: %v", err)
+	}
+	if report.Old != oldPath || report.New != newPath {
+		t.Errorf("old/new = %q/%q, ingin %q/%q", report.Old, report.New, oldPath, newPath)
+	}
+	if len(report.ElementsAdded) != 1 || report.ElementsAdded[0] != "Steam" {
+		t.Errorf("elementsAdded = %v, ingin [Steam]", report.ElementsAdded)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"main/services"
+)
+
+// Report adalah hasil perbandingan dua dataset
+type Report struct {
+	Old             string         `json:"old"`
+	New             string         `json:"new"`
+	ElementsAdded   []string       `json:"elementsAdded"`
+	ElementsRemoved []string       `json:"elementsRemoved"`
+	Recipes         []RecipeChange `json:"recipes"`
+	Icons           []IconChange   `json:"icons"`
+	Depths          []DepthChange  `json:"depths"`
+}
+
+// RecipeChange berisi resep yang ditambah atau dihapus untuk satu elemen
+type RecipeChange struct {
+	Element string   `json:"element"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// IconChange berisi perubahan URL ikon satu elemen
+type IconChange struct {
+	Element string `json:"element"`
+	Old     string `json:"old"`
+	New     string `json:"new"`
+}
+
+// DepthChange berisi perubahan kedalaman resep minimum (-1 jika tidak bisa dibuat)
+type DepthChange struct {
+	Element string `json:"element"`
+	Old     int    `json:"old"`
+	New     int    `json:"new"`
+}
+
+// Empty bernilai true jika kedua dataset identik
+func (r Report) Empty() bool {
+	return len(r.ElementsAdded) == 0 && len(r.ElementsRemoved) == 0 &&
+		len(r.Recipes) == 0 && len(r.Icons) == 0 && len(r.Depths) == 0
+}
+
+// compare membandingkan dataset lama dan baru
+func compare(oldRepo, newRepo services.RecipeRepository) (Report, error) {
+	report := Report{
+		ElementsAdded:   []string{},
+		ElementsRemoved: []string{},
+		Recipes:         []RecipeChange{},
+		Icons:           []IconChange{},
+		Depths:          []DepthChange{},
+	}
+
+	oldElements, err := elementSet(oldRepo)
+	if err != nil {
+		return report, err
+	}
+	newElements, err := elementSet(newRepo)
+	if err != nil {
+		return report, err
+	}
+	oldDepths, err := services.MinDepths(oldRepo)
+	if err != nil {
+		return report, err
+	}
+	newDepths, err := services.MinDepths(newRepo)
+	if err != nil {
+		return report, err
+	}
+
+	for _, element := range sortedUnion(oldElements, newElements) {
+		inOld, inNew := oldElements[element], newElements[element]
+		switch {
+		case inOld && !inNew:
+			report.ElementsRemoved = append(report.ElementsRemoved, element)
+		case !inOld && inNew:
+			report.ElementsAdded = append(report.ElementsAdded, element)
+		}
+
+		// Resep dibandingkan tanpa memperhatikan urutan bahan
+		oldRecipes, err := recipeSet(oldRepo, element)
+		if err != nil {
+			return report, err
+		}
+		newRecipes, err := recipeSet(newRepo, element)
+		if err != nil {
+			return report, err
+		}
+		change := RecipeChange{Element: element}
+		for _, recipe := range sortedUnion(oldRecipes, newRecipes) {
+			if !oldRecipes[recipe] {
+				change.Added = append(change.Added, recipe)
+			} else if !newRecipes[recipe] {
+				change.Removed = append(change.Removed, recipe)
+			}
+		}
+		if len(change.Added) > 0 || len(change.Removed) > 0 {
+			report.Recipes = append(report.Recipes, change)
+		}
+
+		if !inOld || !inNew {
+			continue
+		}
+		if oldIcon, newIcon := oldRepo.ImageURL(element), newRepo.ImageURL(element); oldIcon != newIcon {
+			report.Icons = append(report.Icons, IconChange{Element: element, Old: oldIcon, New: newIcon})
+		}
+		if oldDepth, newDepth := depthOf(oldDepths, element), depthOf(newDepths, element); oldDepth != newDepth {
+			report.Depths = append(report.Depths, DepthChange{Element: element, Old: oldDepth, New: newDepth})
+		}
+	}
+
+	return report, nil
+}
+
+// elementSet mengembalikan himpunan elemen dalam dataset
+func elementSet(repo services.RecipeRepository) (map[string]bool, error) {
+	elements, err := repo.Elements()
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		set[element] = true
+	}
+	return set, nil
+}
+
+// recipeSet mengembalikan himpunan resep elemen dalam bentuk "A + B" dengan bahan terurut
+func recipeSet(repo services.RecipeRepository, element string) (map[string]bool, error) {
+	recipes, err := repo.Recipes(element)
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[string]bool, len(recipes))
+	for _, combo := range recipes {
+		item1, item2 := combo.Item1, combo.Item2
+		if item2 < item1 {
+			item1, item2 = item2, item1
+		}
+		set[item1+" + "+item2] = true
+	}
+	return set, nil
+}
+
+// depthOf mengembalikan kedalaman minimum elemen, atau -1 jika tidak bisa dibuat
+func depthOf(depths map[string]int, element string) int {
+	if depth, ok := depths[element]; ok {
+		return depth
+	}
+	return -1
+}
+
+// sortedUnion mengembalikan gabungan kunci dua himpunan secara terurut
+func sortedUnion(a, b map[string]bool) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if !a[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// writeMarkdown menulis laporan dalam format Markdown untuk ditinjau manusia
+func writeMarkdown(w io.Writer, r Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# Perubahan dataset\n\n`%s` → `%s`\n\n", r.Old, r.New)
+	if r.Empty() {
+		b.WriteString("Tidak ada perubahan.\n")
+		_, err := io.WriteString(w, b.String())
+		return err
+	}
+
+	fmt.Fprintf(&b, "| Perubahan | Jumlah |\n|---|---|\n")
+	fmt.Fprintf(&b, "| Elemen ditambah | %d |\n", len(r.ElementsAdded))
+	fmt.Fprintf(&b, "| Elemen dihapus | %d |\n", len(r.ElementsRemoved))
+	fmt.Fprintf(&b, "| Elemen dengan resep berubah | %d |\n", len(r.Recipes))
+	fmt.Fprintf(&b, "| Ikon berubah | %d |\n", len(r.Icons))
+	fmt.Fprintf(&b, "| Kedalaman minimum berubah | %d |\n", len(r.Depths))
+
+	writeList := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s\n\n", title)
+		for _, item := range items {
+			fmt.Fprintf(&b, "- %s\n", item)
+		}
+	}
+	writeList("Elemen ditambah", r.ElementsAdded)
+	writeList("Elemen dihapus", r.ElementsRemoved)
+
+	if len(r.Recipes) > 0 {
+		b.WriteString("\n## Resep\n\n")
+		for _, change := range r.Recipes {
+			fmt.Fprintf(&b, "### %s\n\n", change.Element)
+			for _, recipe := range change.Added {
+				fmt.Fprintf(&b, "- ➕ %s\n", recipe)
+			}
+			for _, recipe := range change.Removed {
+				fmt.Fprintf(&b, "- ➖ %s\n", recipe)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	if len(r.Icons) > 0 {
+		b.WriteString("\n## Ikon\n\n| Elemen | Lama | Baru |\n|---|---|---|\n")
+		for _, change := range r.Icons {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", change.Element, change.Old, change.New)
+		}
+	}
+
+	if len(r.Depths) > 0 {
+		b.WriteString("\n## Kedalaman resep minimum\n\n| Elemen | Lama | Baru |\n|---|---|---|\n")
+		for _, change := range r.Depths {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", change.Element, formatDepth(change.Old), formatDepth(change.New))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatDepth menampilkan kedalaman, atau "-" jika elemen tidak bisa dibuat
+func formatDepth(depth int) string {
+	if depth < 0 {
+		return "-"
+	}
+	return fmt.Sprint(depth)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"main/services"
+)
+
+// oldRows dan newRows adalah dua versi dataset kecil:
+//
+//	Steam = Water + Fire (urutan bahan dibalik di versi baru, ikon berubah)
+//	Cloud = Steam + Air, versi baru menambah Water + Air (kedalaman 2 -> 1)
+//	Mist  = Water + Air, dihapus di versi baru
+//	Rain  = Cloud + Water, ditambah di versi baru
+var (
+	oldRows = []services.ElementRow{
+		{Element: "Air"},
+		{Element: "Fire"},
+		{Element: "Water"},
+		{Element: "Steam", Item1: "Water", Item2: "Fire"},
+		{Element: "Cloud", Item1: "Steam", Item2: "Air"},
+		{Element: "Mist", Item1: "Water", Item2: "Air"},
+	}
+	newRows = []services.ElementRow{
+		{Element: "Air"},
+		{Element: "Fire"},
+		{Element: "Water"},
+		{Element: "Steam", Item1: "Fire", Item2: "Water"},
+		{Element: "Cloud", Item1: "Steam", Item2: "Air"},
+		{Element: "Cloud", Item1: "Water", Item2: "Air"},
+		{Element: "Rain", Item1: "Cloud", Item2: "Water"},
+	}
+)
+
+func TestCompare(t *testing.T) {
+	oldRepo := services.NewMemoryRepository(oldRows, map[string]string{"Steam": "steam-lama.svg"})
+	newRepo := services.NewMemoryRepository(newRows, map[string]string{"Steam": "steam-baru.svg"})
+
+	report, err := compare(oldRepo, newRepo)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+
+	want := Report{
+		ElementsAdded:   []string{"Rain"},
+		ElementsRemoved: []string{"Mist"},
+		Recipes: []RecipeChange{
+			{Element: "Cloud", Added: []string{"Air + Water"}},
+			{Element: "Mist", Removed: []string{"Air + Water"}},
+			{Element: "Rain", Added: []string{"Cloud + Water"}},
+		},
+		Icons:  []IconChange{{Element: "Steam", Old: "steam-lama.svg", New: "steam-baru.svg"}},
+		Depths: []DepthChange{{Element: "Cloud", Old: 2, New: 1}},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("compare:\n got %+v\nwant %+v", report, want)
+	}
+}
+
+func TestCompareIdentical(t *testing.T) {
+	repo := services.NewMemoryRepository(oldRows, nil)
+
+	report, err := compare(repo, repo)
+	if err != nil {
+		t.Fatalf("compare: %v", err)
+	}
+	if !report.Empty() {
+		t.Errorf("dataset yang sama harus menghasilkan laporan kosong, dapat %+v", report)
+	}
+}
+
+func TestWriteMarkdown(t *testing.T) {
+	tests := []struct {
+		name    string
+		report  Report
+		want    []string
+		notWant []string
+	}{
+		{
+			name:    "tanpa perubahan",
+			report:  Report{Old: "lama.db", New: "baru.db"},
+			want:    []string{"`lama.db` → `baru.db`", "Tidak ada perubahan."},
+			notWant: []string{"| Perubahan | Jumlah |"},
+		},
+		{
+			name: "dengan perubahan",
+			report: Report{
+				Old:             "lama.db",
+				New:             "baru.db",
+				ElementsAdded:   []string{"Rain"},
+				ElementsRemoved: []string{"Mist"},
+				Recipes: []RecipeChange{
+					{Element: "Cloud", Added: []string{"Air + Water"}, Removed: []string{"Air + Steam"}},
+				},
+				Icons:  []IconChange{{Element: "Steam", Old: "a.svg", New: "b.svg"}},
+				Depths: []DepthChange{{Element: "Cloud", Old: 2, New: -1}},
+			},
+			want: []string{
+				"| Elemen ditambah | 1 |",
+				"| Elemen dihapus | 1 |",
+				"## Elemen ditambah\n\n- Rain\n",
+				"## Elemen dihapus\n\n- Mist\n",
+				"### Cloud\n\n- ➕ Air + Water\n- ➖ Air + Steam\n",
+				"| Steam | a.svg | b.svg |",
+				"| Cloud | 2 | - |",
+			},
+			notWant: []string{"Tidak ada perubahan."},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			if err := writeMarkdown(&b, tt.report); err != nil {
+				t.Fatalf("writeMarkdown: %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("laporan tidak berisi %q:\n%s", want, b.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(b.String(), notWant) {
+					t.Errorf("laporan tidak boleh berisi %q:\n%s", notWant, b.String())
+				}
+			}
+		})
+	}
+}
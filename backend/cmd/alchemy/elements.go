@@ -0,0 +1,95 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"main/services"
+)
+
+// runElements menjalankan perintah "elements": satu nama elemen per baris
+func runElements(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("elements", flag.ContinueOnError)
+	basicOnly := fs.Bool("basic", false, "hanya elemen dasar")
+	tier := fs.Int("tier", -1, "hanya elemen dengan kedalaman resep minimum ini")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	elements, err := repo.Elements()
+	if err != nil {
+		return err
+	}
+	if *basicOnly {
+		if elements, err = repo.BasicElements(); err != nil {
+			return err
+		}
+	}
+
+	var depths map[string]int
+	if *tier >= 0 {
+		if depths, err = services.MinDepths(repo); err != nil {
+			return err
+		}
+	}
+
+	var b strings.Builder
+	for _, element := range elements {
+		if depth, ok := depths[element]; depths != nil && (!ok || depth != *tier) {
+			continue
+		}
+		b.WriteString(element + "\n")
+	}
+	_, err = io.WriteString(out, b.String())
+	return err
+}
+
+// runStats menjalankan perintah "stats": ringkasan ukuran dan kedalaman dataset
+func runStats(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	elements, err := repo.Elements()
+	if err != nil {
+		return err
+	}
+	basicElements, err := repo.BasicElements()
+	if err != nil {
+		return err
+	}
+	depths, err := services.MinDepths(repo)
+	if err != nil {
+		return err
+	}
+
+	recipes, maxDepth := 0, 0
+	perTier := make(map[int]int)
+	for _, element := range elements {
+		combinations, err := repo.Recipes(element)
+		if err != nil {
+			return err
+		}
+		recipes += len(combinations)
+		if depth, ok := depths[element]; ok {
+			perTier[depth]++
+			maxDepth = max(maxDepth, depth)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Elemen:            %d\n", len(elements))
+	fmt.Fprintf(&b, "Elemen dasar:      %d (%s)\n", len(basicElements), strings.Join(basicElements, ", "))
+	fmt.Fprintf(&b, "Resep:             %d\n", recipes)
+	fmt.Fprintf(&b, "Bisa dibuat:       %d\n", len(depths))
+	fmt.Fprintf(&b, "Tidak bisa dibuat: %d\n", len(elements)-len(depths))
+	fmt.Fprintf(&b, "Kedalaman maks:    %d\n\nElemen per tier:\n", maxDepth)
+	for depth := 0; depth <= maxDepth; depth++ {
+		fmt.Fprintf(&b, "  %2d  %d\n", depth, perTier[depth])
+	}
+	_, err = io.WriteString(out, b.String())
+	return err
+}
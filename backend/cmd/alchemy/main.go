@@ -0,0 +1,171 @@
+// File ini adalah command line client pencarian resep Little Alchemy 2.
+// Menjalankan algoritma yang sama dengan backend (BFS, DFS, Bidirectional) tanpa server web.
+//
+// Penggunaan:
+//
+//	alchemy [-db path] [-mapper path] [-dataset nama] <perintah> [argumen]
+//
+// Perintah:
+//
+//	search <elemen> [-algo BFS|DFS|Bidirectional] [-type One|Limit|All] [-max n] [-format ascii|json|dot]
+//	elements [-basic] [-tier n]
+//	stats
+//	plan <elemen> [-format ascii|json]
+//	route [-summary] [-format ascii|json]
+//	trace <elemen> [-algo BFS|DFS|Bidirectional] [-vs algoritma] [-kinds visit,push,...] [-o file] [-format ascii|json]
+//	replay <trace.json> [trace-pembanding.json] [-kinds visit,push,...] [-format ascii|json]
+//	repl
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"main/services"
+)
+
+// Kode keluar program
+const (
+	exitOK       = 0 // Berhasil
+	exitNotFound = 1 // Elemen tidak ada atau resep tidak ditemukan
+	exitError    = 2 // Argumen salah atau dataset gagal dibuka
+)
+
+// errUsage menandai argumen yang salah; pesan penggunaan sudah dicetak oleh flag
+var errUsage = errors.New("argumen tidak valid")
+
+// command adalah satu subcommand CLI
+type command struct {
+	name    string
+	summary string
+	run     func(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error
+}
+
+var commands = []command{
+	{name: "search", summary: "cari resep elemen dengan BFS, DFS atau Bidirectional", run: runSearch},
+	{name: "elements", summary: "tampilkan daftar elemen", run: runElements},
+	{name: "stats", summary: "tampilkan statistik dataset", run: runStats},
+	{name: "plan", summary: "tampilkan urutan kombinasi untuk membuat elemen dari elemen dasar", run: runPlan},
+	{name: "route", summary: "tampilkan urutan kombinasi untuk membuka semua elemen dari elemen dasar", run: runRoute},
+	{name: "trace", summary: "rekam setiap langkah traversal pencarian, atau bandingkan dua algoritma", run: runTrace},
+	{name: "replay", summary: "putar ulang trace JSON, atau bandingkan dua trace", run: runReplay},
+	{name: "repl", summary: "buka shell interaktif untuk menjelajahi graf resep", run: runRepl},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run menjalankan CLI dan mengembalikan kode keluar
+func run(args []string, out, errOut io.Writer) int {
+	global := flag.NewFlagSet("alchemy", flag.ContinueOnError)
+	global.SetOutput(errOut)
+	dbPath := global.String("db", "", "dataset SQLite, CSV atau JSON (default: ALCHEMY_DB_PATH atau ../database/alchemy.db)")
+	mapperPath := global.String("mapper", "", "mapper ikon JSON (default: ALCHEMY_MAPPER_PATH atau ../database/mapper2.json)")
+	dataset := global.String("dataset", "", "nama dataset (default: la2)")
+	global.Usage = func() {
+		fmt.Fprintf(errOut, "Penggunaan: alchemy [flag] <perintah> [argumen]\n\nPerintah:\n")
+		for _, cmd := range commands {
+			fmt.Fprintf(errOut, "  %-9s %s\n", cmd.name, cmd.summary)
+		}
+		fmt.Fprintf(errOut, "\nFlag:\n")
+		global.PrintDefaults()
+	}
+	if err := global.Parse(args); err != nil {
+		return exitError
+	}
+	if global.NArg() == 0 {
+		global.Usage()
+		return exitError
+	}
+
+	var cmd *command
+	for i := range commands {
+		if commands[i].name == global.Arg(0) {
+			cmd = &commands[i]
+		}
+	}
+	if cmd == nil {
+		fmt.Fprintf(errOut, "Perintah tidak dikenal: %s\n\n", global.Arg(0))
+		global.Usage()
+		return exitError
+	}
+
+	catalog, err := openCatalog(*dbPath, *mapperPath)
+	if err != nil {
+		fmt.Fprintf(errOut, "Gagal membuka dataset: %v\n", err)
+		return exitError
+	}
+	searcher, ok := catalog.Searcher(*dataset)
+	if !ok {
+		fmt.Fprintf(errOut, "Dataset tidak dikenal: %s (tersedia: %v)\n", *dataset, catalog.Names())
+		return exitError
+	}
+
+	err = cmd.run(searcher.Repository(), searcher, global.Args()[1:], out)
+	switch {
+	case err == nil:
+		return exitOK
+	case errors.Is(err, errUsage):
+		return exitError
+	case errors.Is(err, services.ErrElementNotFound), errors.Is(err, services.ErrNoRecipe), errors.Is(err, services.ErrNoPath):
+		fmt.Fprintf(errOut, "%v\n", err)
+		return exitNotFound
+	default:
+		fmt.Fprintf(errOut, "Error: %v\n", err)
+		return exitError
+	}
+}
+
+// openCatalog membuka dataset dari flag, atau konfigurasi default backend jika flag kosong
+func openCatalog(dbPath, mapperPath string) (*services.Catalog, error) {
+	if dbPath == "" && mapperPath == "" {
+		return services.OpenDefaultCatalog()
+	}
+	if dbPath == "" {
+		return nil, errors.New("flag -mapper membutuhkan -db")
+	}
+	return services.OpenCatalog(dbPath, mapperPath)
+}
+
+// resolveElement mencocokkan nama elemen tanpa peduli huruf besar, aksen, atau spasi.
+// Jika tidak ada, error menyertakan nama-nama yang mirip.
+func resolveElement(searcher *services.Searcher, name string) (string, error) {
+	element, ok, err := searcher.Resolve(name)
+	if err != nil {
+		return "", err
+	}
+	if ok {
+		return element, nil
+	}
+
+	suggestions, err := searcher.Suggest(name, 3)
+	if err != nil || len(suggestions) == 0 {
+		return "", fmt.Errorf("%s: %w", name, services.ErrElementNotFound)
+	}
+	names := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		names[i] = suggestion.Name
+	}
+	return "", fmt.Errorf("%s: %w (maksud Anda: %s?)", name, services.ErrElementNotFound, strings.Join(names, ", "))
+}
+
+// parseArgs mem-parse flag subcommand yang boleh diletakkan sebelum maupun sesudah
+// argumen posisi, misalnya "search Brick -algo DFS"
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, errUsage
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fixtureCSV adalah graf resep kecil untuk test CLI:
+//
+//	Mud   = Water + Earth
+//	Lava  = Earth + Fire
+//	Stone = Lava + Air
+//	Brick = Mud + Fire | Stone + Fire
+//	Ghost = Ghost + Ghost (tidak bisa dibuat dari elemen dasar)
+const fixtureCSV = `Element,Item1,Item2
+Air,,
+Earth,,
+Fire,,
+Water,,
+Mud,Water,Earth
+Lava,Earth,Fire
+Stone,Lava,Air
+Brick,Mud,Fire
+Brick,Stone,Fire
+Ghost,Ghost,Ghost
+`
+
+// writeFixture menulis fixtureCSV ke direktori sementara dan mengembalikan path-nya
+func writeFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "alchemy.csv")
+	if err := os.WriteFile(path, []byte(fixtureCSV), 0o644); err != nil {
+		t.Fatalf("menulis fixture: %v", err)
+	}
+	return path
+}
+
+// Pesan penggunaan subcommand dicetak flag.FlagSet ke os.Stderr, jadi kasus
+// argumen salah pada subcommand hanya memeriksa kode keluar.
+func TestRun(t *testing.T) {
+	db := writeFixture(t)
+
+	tracePath := filepath.Join(t.TempDir(), "brick.json")
+
+	tests := []struct {
+		name       string
+		args       []string
+		wantCode   int
+		wantOut    string
+		wantErrOut string
+	}{
+		{name: "tanpa perintah", args: []string{"-db", db}, wantCode: exitError, wantErrOut: "Penggunaan: alchemy"},
+		{name: "flag global tidak dikenal", args: []string{"-x", "stats"}, wantCode: exitError, wantErrOut: "-x"},
+		{name: "perintah tidak dikenal", args: []string{"-db", db, "fly"}, wantCode: exitError, wantErrOut: "Perintah tidak dikenal: fly"},
+		{name: "mapper tanpa db", args: []string{"-mapper", "mapper.json", "stats"}, wantCode: exitError, wantErrOut: "flag -mapper membutuhkan -db"},
+		{name: "dataset tidak ada", args: []string{"-db", filepath.Join(t.TempDir(), "tidak-ada.csv"), "stats"}, wantCode: exitError, wantErrOut: "Gagal membuka dataset"},
+		{name: "dataset tidak dikenal", args: []string{"-db", db, "-dataset", "myths", "stats"}, wantCode: exitError, wantErrOut: "Dataset tidak dikenal: myths"},
+
+		{name: "search", args: []string{"-db", db, "search", "Brick"}, wantCode: exitOK, wantOut: "Resep 1/1\nBrick\n"},
+		{name: "search flag setelah elemen", args: []string{"-db", db, "search", "Brick", "-algo", "DFS", "-type", "All"}, wantCode: exitOK, wantOut: "DFS: 2 resep"},
+		{name: "search dengan inventory", args: []string{"-db", db, "search", "Brick", "-type", "All", "-owned", "Stone"}, wantCode: exitOK, wantOut: "Resep 1/2 (1 kombinasi baru)\nBrick\n"},
+		{name: "search dengan batasan", args: []string{"-db", db, "search", "Brick", "-type", "All", "-exclude", "Mud + Fire", "-require", "Air"}, wantCode: exitOK, wantOut: "BFS: 1 resep"},
+		{name: "search tanpa elemen", args: []string{"-db", db, "search"}, wantCode: exitError},
+		{name: "search format tidak dikenal", args: []string{"-db", db, "search", "Brick", "-format", "yaml"}, wantCode: exitError},
+		{name: "search algoritma tidak dikenal", args: []string{"-db", db, "search", "Brick", "-algo", "A*"}, wantCode: exitError},
+		{name: "search elemen tidak ada", args: []string{"-db", db, "search", "Unicorn"}, wantCode: exitNotFound, wantErrOut: "Unicorn: element not found"},
+		{name: "search tanpa resep", args: []string{"-db", db, "search", "Ghost"}, wantCode: exitNotFound, wantErrOut: "Ghost: no recipe"},
+
+		{name: "elements dasar", args: []string{"-db", db, "elements", "-basic"}, wantCode: exitOK, wantOut: "Air\nEarth\nFire\nWater\n"},
+		{name: "elements per tier", args: []string{"-db", db, "elements", "-tier", "2"}, wantCode: exitOK, wantOut: "Stone\nBrick\n"},
+		{name: "stats", args: []string{"-db", db, "stats"}, wantCode: exitOK, wantOut: "Elemen:            9\n"},
+
+		{name: "plan", args: []string{"-db", db, "plan", "Brick"}, wantCode: exitOK, wantOut: "  2. Brick = Mud + Fire\n"},
+		{name: "plan elemen dasar", args: []string{"-db", db, "plan", "Fire"}, wantCode: exitOK, wantOut: "Fire adalah elemen dasar"},
+		{name: "plan tanpa resep", args: []string{"-db", db, "plan", "Ghost"}, wantCode: exitNotFound, wantErrOut: "Ghost: no recipe"},
+		{name: "route", args: []string{"-db", db, "route"}, wantCode: exitOK, wantOut: "-- Tier 2 --\n   3. Air + Lava = Stone\n"},
+		{name: "route ringkasan", args: []string{"-db", db, "route", "-summary"}, wantCode: exitOK, wantOut: "4 kombinasi membuka 8 elemen (4 elemen dasar), 1 elemen tidak terjangkau\n"},
+		{name: "route dengan argumen", args: []string{"-db", db, "route", "Brick"}, wantCode: exitError},
+		{name: "trace", args: []string{"-db", db, "trace", "Brick", "-kinds", "recipe"}, wantCode: exitOK, wantOut: "recipe  Brick: Brick = Mud + Fire"},
+		{name: "trace ke file", args: []string{"-db", db, "trace", "Brick", "-o", tracePath}, wantCode: exitOK, wantOut: "Trace disimpan di " + tracePath},
+		{name: "replay file trace", args: []string{"-db", db, "replay", tracePath, "-kinds", "recipe"}, wantCode: exitOK, wantOut: "BFS Brick: 9 langkah"},
+		{name: "trace dibandingkan", args: []string{"-db", db, "trace", "Brick", "-vs", "DFS"}, wantCode: exitOK, wantOut: "Langkah pertama yang berbeda: #3"},
+		{name: "trace algoritma tidak dikenal", args: []string{"-db", db, "trace", "Brick", "-algo", "A*"}, wantCode: exitError},
+		{name: "replay file tidak ada", args: []string{"-db", db, "replay", filepath.Join(t.TempDir(), "tidak-ada.json")}, wantCode: exitError},
+		{name: "plan format tidak dikenal", args: []string{"-db", db, "plan", "Brick", "-format", "dot"}, wantCode: exitError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out, errOut strings.Builder
+			code := run(tt.args, &out, &errOut)
+			if code != tt.wantCode {
+				t.Fatalf("kode keluar = %d, ingin %d (stderr: %s)", code, tt.wantCode, errOut.String())
+			}
+			if !strings.Contains(out.String(), tt.wantOut) {
+				t.Errorf("stdout tidak berisi %q:\n%s", tt.wantOut, out.String())
+			}
+			if !strings.Contains(errOut.String(), tt.wantErrOut) {
+				t.Errorf("stderr tidak berisi %q:\n%s", tt.wantErrOut, errOut.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"main/services"
+)
+
+// runPlan menjalankan perintah "plan <elemen>": urutan kombinasi dari elemen dasar
+func runPlan(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("plan", flag.ContinueOnError)
+	format := fs.String("format", "ascii", "format keluaran: ascii atau json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Penggunaan: alchemy plan <elemen> [flag]\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || (*format != "ascii" && *format != "json") {
+		fs.Usage()
+		return errUsage
+	}
+
+	element, err := resolveElement(searcher, positional[0])
+	if err != nil {
+		return err
+	}
+	steps, err := services.CraftingPlan(repo, element)
+	if err != nil {
+		return fmt.Errorf("%s: %w", element, err)
+	}
+
+	if *format == "json" {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			Element string                `json:"element"`
+			Steps   []services.RecipeStep `json:"steps"`
+		}{element, steps})
+	}
+
+	var b strings.Builder
+	if len(steps) == 0 {
+		fmt.Fprintf(&b, "%s adalah elemen dasar\n", element)
+	}
+	for i, step := range steps {
+		fmt.Fprintf(&b, "%3d. %s = %s + %s\n", i+1, step.Result, step.Item1, step.Item2)
+	}
+	_, err = io.WriteString(out, b.String())
+	return err
+}
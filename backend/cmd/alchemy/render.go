@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"main/services"
+)
+
+// searchOutput adalah hasil pencarian yang akan dicetak, sama dengan respons /api/search
+type searchOutput struct {
+	Element       string                 `json:"element"`
+	Algorithm     string                 `json:"algorithm"`
+	Status        services.SearchStatus  `json:"status"`
+	Message       string                 `json:"message"`
+	Results       []*services.RecipeTree `json:"results"`
+	NodesVisited  int                    `json:"nodesVisited"`
+	ExecutionTime float64                `json:"executionTime"`
+}
+
+// newSearchOutput menyiapkan hasil pencarian untuk dicetak
+func newSearchOutput(element, algorithm string, result *services.SearchResult) searchOutput {
+	return searchOutput{
+		Element:       element,
+		Algorithm:     algorithm,
+		Status:        result.Status,
+		Message:       result.Message,
+		Results:       result.Results,
+		NodesVisited:  result.NodesVisited,
+		ExecutionTime: result.ExecutionTime,
+	}
+}
+
+// renderers memetakan nama format ke fungsi pencetak hasil pencarian
+var renderers = map[string]func(io.Writer, searchOutput) error{
+	"ascii": renderASCII,
+	"json":  renderJSON,
+	"dot":   renderDOT,
+}
+
+// renderASCII mencetak setiap resep sebagai pohon bertingkat beserta langkah-langkahnya
+func renderASCII(w io.Writer, output searchOutput) error {
+	var b strings.Builder
+	for i, tree := range output.Results {
+		fmt.Fprintf(&b, "Resep %d/%d", i+1, len(output.Results))
+		if tree.NewCombinations > 0 {
+			fmt.Fprintf(&b, " (%d kombinasi baru)", tree.NewCombinations)
+		}
+		b.WriteString("\n")
+		b.WriteString(tree.Name + "\n")
+		writeASCIIChildren(&b, tree.Children, "")
+		for _, step := range tree.Recipe {
+			fmt.Fprintf(&b, "  %s\n", step)
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%s: %d resep, %d node dikunjungi, %.0f ms",
+		output.Algorithm, len(output.Results), output.NodesVisited, output.ExecutionTime)
+	if output.Status == services.StatusLimitReached {
+		b.WriteString(" (batas jumlah resep tercapai)")
+	}
+	b.WriteString("\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeASCIIChildren mencetak anak-anak node dengan garis cabang
+func writeASCIIChildren(b *strings.Builder, children []*services.RecipeTree, prefix string) {
+	for i, child := range children {
+		branch, indent := "|-- ", "|   "
+		if i == len(children)-1 {
+			branch, indent = "`-- ", "    "
+		}
+		b.WriteString(prefix + branch + child.Name)
+		if child.Owned {
+			b.WriteString(" (owned)")
+		}
+		b.WriteString("\n")
+		writeASCIIChildren(b, child.Children, prefix+indent)
+	}
+}
+
+// renderJSON mencetak hasil dalam format JSON
+func renderJSON(w io.Writer, output searchOutput) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(output)
+}
+
+// renderDOT mencetak hasil sebagai graf Graphviz, satu cluster per resep
+func renderDOT(w io.Writer, output searchOutput) error {
+	var b strings.Builder
+	b.WriteString("digraph recipes {\n\trankdir=TB;\n\tnode [shape=box];\n")
+
+	id := 0
+	var writeNode func(tree *services.RecipeTree) int
+	writeNode = func(tree *services.RecipeTree) int {
+		nodeID := id
+		id++
+		fmt.Fprintf(&b, "\t\tn%d [label=%q];\n", nodeID, tree.Name)
+		for _, child := range tree.Children {
+			childID := writeNode(child)
+			fmt.Fprintf(&b, "\t\tn%d -> n%d;\n", nodeID, childID)
+		}
+		return nodeID
+	}
+
+	for i, tree := range output.Results {
+		fmt.Fprintf(&b, "\tsubgraph cluster_%d {\n\t\tlabel=%q;\n", i, fmt.Sprintf("Resep %d", i+1))
+		writeNode(tree)
+		b.WriteString("\t}\n")
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
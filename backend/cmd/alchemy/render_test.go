@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"main/services"
+)
+
+// brickOutput adalah hasil pencarian Brick = Mud + Fire dengan Mud = Water + Earth
+func brickOutput() searchOutput {
+	leaf := func(name string) *services.RecipeTree {
+		return &services.RecipeTree{Name: name, Children: []*services.RecipeTree{}}
+	}
+	return searchOutput{
+		Element:   "Brick",
+		Algorithm: "BFS",
+		Results: []*services.RecipeTree{{
+			Name: "Brick",
+			Children: []*services.RecipeTree{
+				{Name: "Mud", Children: []*services.RecipeTree{leaf("Water"), leaf("Earth")}},
+				leaf("Fire"),
+			},
+			Recipe: []string{"Mud = Water + Earth", "Brick = Mud + Fire"},
+		}},
+		NodesVisited:  7,
+		ExecutionTime: 3,
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "ascii",
+			want: "Resep 1/1\n" +
+				"Brick\n" +
+				"|-- Mud\n" +
+				"|   |-- Water\n" +
+				"|   `-- Earth\n" +
+				"`-- Fire\n" +
+				"  Mud = Water + Earth\n" +
+				"  Brick = Mud + Fire\n" +
+				"\n" +
+				"BFS: 1 resep, 7 node dikunjungi, 3 ms\n",
+		},
+		{
+			format: "dot",
+			want: "digraph recipes {\n\trankdir=TB;\n\tnode [shape=box];\n" +
+				"\tsubgraph cluster_0 {\n\t\tlabel=\"Resep 1\";\n" +
+				"\t\tn0 [label=\"Brick\"];\n" +
+				"\t\tn1 [label=\"Mud\"];\n" +
+				"\t\tn2 [label=\"Water\"];\n" +
+				"\t\tn1 -> n2;\n" +
+				"\t\tn3 [label=\"Earth\"];\n" +
+				"\t\tn1 -> n3;\n" +
+				"\t\tn0 -> n1;\n" +
+				"\t\tn4 [label=\"Fire\"];\n" +
+				"\t\tn0 -> n4;\n" +
+				"\t}\n}\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var b strings.Builder
+			if err := renderers[tt.format](&b, brickOutput()); err != nil {
+				t.Fatalf("render: %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("keluaran %s:\n%s\ningin:\n%s", tt.format, b.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderASCIIOwned(t *testing.T) {
+	output := brickOutput()
+	mud := output.Results[0].Children[0]
+	mud.Owned, mud.Children = true, []*services.RecipeTree{}
+
+	var b strings.Builder
+	if err := renderASCII(&b, output); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if !strings.Contains(b.String(), "Brick\n|-- Mud (owned)\n`-- Fire\n") {
+		t.Errorf("elemen yang dimiliki tidak ditandai (owned):\n%s", b.String())
+	}
+	for _, r := range b.String() {
+		if r > 127 {
+			t.Fatalf("keluaran ascii berisi karakter non-ASCII %q:\n%s", r, b.String())
+		}
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	var b strings.Builder
+	if err := renderJSON(&b, brickOutput()); err != nil {
+		t.Fatalf("renderJSON: %v", err)
+	}
+
+	var decoded searchOutput
+	if err := json.Unmarshal([]byte(b.String()), &decoded); err != nil {
+		t.Fatalf("keluaran bukan JSON yang valid: %v", err)
+	}
+	if decoded.Element != "Brick" || len(decoded.Results) != 1 || len(decoded.Results[0].Children) != 2 {
+		t.Errorf("JSON tidak sesuai hasil pencarian: %s", b.String())
+	}
+}
@@ -0,0 +1,567 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"main/services"
+
+	"golang.org/x/term"
+)
+
+// session adalah state shell interaktif: dataset di memori dan inventory pemain
+type session struct {
+	repo      services.RecipeRepository
+	searcher  *services.Searcher
+	names     []string        // Semua nama elemen terurut, untuk tab completion
+	inventory map[string]bool // Elemen yang sudah ditemukan selama sesi
+	last      *searchOutput   // Hasil search terakhir, untuk export
+	out       io.Writer
+}
+
+// replCommand adalah satu perintah di dalam shell
+type replCommand struct {
+	usage   string
+	summary string
+	run     func(s *session, args []string) error
+}
+
+var replCommands map[string]replCommand
+
+func init() {
+	// Diisi di init karena perintah "help" membaca map ini sendiri
+	replCommands = map[string]replCommand{
+		"help":     {"help", "tampilkan daftar perintah", (*session).help},
+		"recipes":  {"recipes <elemen>", "kombinasi yang membuat elemen", (*session).recipes},
+		"uses":     {"uses <elemen>", "elemen yang bisa dibuat dengan elemen ini", (*session).uses},
+		"search":   {"search <elemen> [-algo BFS] [-type One] [-max 5] [-inv]", "cari pohon resep (-inv: mulai dari inventory)", (*session).search},
+		"shortest": {"shortest <dari> <ke>", "rantai kombinasi terpendek dari satu elemen ke elemen lain", (*session).shortest},
+		"count":    {"count <elemen> [-algo BFS]", "hitung semua resep yang ditemukan pencarian All", (*session).count},
+		"export":   {"export <file>", "simpan hasil search terakhir (.json, .dot, atau teks)", (*session).export},
+		"inv":      {"inv", "tampilkan elemen yang sudah ditemukan", (*session).showInventory},
+		"discover": {"discover <elemen>...", "tandai elemen sebagai sudah ditemukan", (*session).discover},
+		"forget":   {"forget <elemen>...", "hapus elemen dari inventory", (*session).forget},
+		"craft":    {"craft <elemen> <elemen>", "gabungkan dua elemen dari inventory", (*session).craft},
+		"hint":     {"hint [tujuan]", "sarankan kombinasi berikutnya dari inventory", (*session).hint},
+		"quit":     {"quit", "keluar", nil},
+	}
+}
+
+// runRepl menjalankan perintah "repl"
+func runRepl(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("repl", flag.ContinueOnError)
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	// Salin dataset ke memori supaya setiap query langsung dijawab
+	memory, err := services.LoadMemoryRepository(repo)
+	if err != nil {
+		return err
+	}
+	s, err := newSession(memory, out)
+	if err != nil {
+		return err
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		// Input dari pipe atau file: baca per baris tanpa prompt
+		scanner := bufio.NewScanner(os.Stdin)
+		for scanner.Scan() {
+			if !s.execute(scanner.Text()) {
+				break
+			}
+		}
+		return scanner.Err()
+	}
+
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	terminal := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{os.Stdin, out}, "alchemy> ")
+	terminal.AutoCompleteCallback = s.completer(terminal)
+	s.out = terminal
+
+	fmt.Fprintf(terminal, "%d elemen dimuat. Ketik \"help\" untuk daftar perintah, Tab untuk melengkapi nama.\n", len(s.names))
+	for {
+		line, err := terminal.ReadLine()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if !s.execute(line) {
+			return nil
+		}
+	}
+}
+
+// newSession menyiapkan sesi dengan inventory awal berisi elemen dasar
+func newSession(repo services.RecipeRepository, out io.Writer) (*session, error) {
+	elements, err := repo.Elements()
+	if err != nil {
+		return nil, err
+	}
+	basicElements, err := repo.BasicElements()
+	if err != nil {
+		return nil, err
+	}
+
+	s := &session{
+		repo:      repo,
+		searcher:  services.NewSearcher(repo),
+		names:     append([]string(nil), elements...),
+		inventory: make(map[string]bool),
+		out:       out,
+	}
+	sort.Strings(s.names)
+	for _, basic := range basicElements {
+		s.inventory[basic] = true
+	}
+	return s, nil
+}
+
+// execute menjalankan satu baris perintah; false berarti sesi selesai
+func (s *session) execute(line string) bool {
+	args := splitLine(line)
+	if len(args) == 0 {
+		return true
+	}
+	if args[0] == "quit" || args[0] == "exit" {
+		return false
+	}
+
+	cmd, ok := replCommands[args[0]]
+	if !ok {
+		fmt.Fprintf(s.out, "Perintah tidak dikenal: %s (ketik \"help\")\n", args[0])
+		return true
+	}
+	if err := cmd.run(s, args[1:]); err != nil {
+		if errors.Is(err, errUsage) {
+			fmt.Fprintf(s.out, "Penggunaan: %s\n", cmd.usage)
+		} else {
+			fmt.Fprintf(s.out, "%v\n", err)
+		}
+	}
+	return true
+}
+
+// resolve mencari nama elemen tanpa memperhatikan huruf besar/kecil, aksen, atau spasi
+func (s *session) resolve(name string) (string, error) {
+	return resolveElement(s.searcher, name)
+}
+
+func (s *session) help(args []string) error {
+	names := make([]string, 0, len(replCommands))
+	for name := range replCommands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&b, "  %-48s %s\n", replCommands[name].usage, replCommands[name].summary)
+	}
+	b.WriteString("Nama elemen yang mengandung spasi ditulis dalam tanda kutip, misalnya \"Acid rain\".\n")
+	_, err := io.WriteString(s.out, b.String())
+	return err
+}
+
+func (s *session) recipes(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	element, err := s.resolve(args[0])
+	if err != nil {
+		return err
+	}
+	recipes, err := s.repo.Recipes(element)
+	if err != nil {
+		return err
+	}
+
+	if len(recipes) == 0 {
+		fmt.Fprintf(s.out, "%s tidak punya resep (elemen dasar)\n", element)
+		return nil
+	}
+	for _, combo := range recipes {
+		fmt.Fprintf(s.out, "  %s = %s + %s\n", element, combo.Item1, combo.Item2)
+	}
+	return nil
+}
+
+func (s *session) uses(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	element, err := s.resolve(args[0])
+	if err != nil {
+		return err
+	}
+	products, err := s.repo.Products(element)
+	if err != nil {
+		return err
+	}
+
+	if len(products) == 0 {
+		fmt.Fprintf(s.out, "%s tidak dipakai di resep mana pun\n", element)
+		return nil
+	}
+	for _, product := range products {
+		fmt.Fprintf(s.out, "  %s + %s = %s\n", element, product.Partner, product.Result)
+	}
+	return nil
+}
+
+func (s *session) search(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	algorithm := fs.String("algo", "BFS", "")
+	recipeType := fs.String("type", "One", "")
+	maxRecipes := fs.Int("max", 5, "")
+	useInventory := fs.Bool("inv", false, "")
+	positional, err := parseArgs(fs, args)
+	if err != nil || len(positional) != 1 {
+		return errUsage
+	}
+	element, err := s.resolve(positional[0])
+	if err != nil {
+		return err
+	}
+
+	var opts services.SearchOptions
+	if *useInventory {
+		opts.Owned = s.owned()
+	}
+	result, err := s.searcher.Search(*algorithm, element, *recipeType, *maxRecipes, opts)
+	if errors.Is(err, services.ErrUnknownAlgorithm) {
+		return fmt.Errorf("algoritma tidak dikenal: %s", *algorithm)
+	}
+	if err != nil {
+		return err
+	}
+	if err := statusError(element, result); err != nil {
+		return err
+	}
+	if result.Status == services.StatusBasicElement {
+		fmt.Fprintf(s.out, "%s: %s\n", element, result.Message)
+		return nil
+	}
+
+	output := newSearchOutput(element, *algorithm, result)
+	s.last = &output
+	return renderASCII(s.out, output)
+}
+
+func (s *session) shortest(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	from, err := s.resolve(args[0])
+	if err != nil {
+		return err
+	}
+	to, err := s.resolve(args[1])
+	if err != nil {
+		return err
+	}
+
+	steps, err := services.ShortestPath(s.repo, from, to)
+	if err != nil {
+		return fmt.Errorf("%s -> %s: %w", from, to, err)
+	}
+	fmt.Fprintf(s.out, "%s -> %s: %d langkah\n", from, to, len(steps))
+	for i, step := range steps {
+		fmt.Fprintf(s.out, "%3d. %s + %s = %s\n", i+1, step.Item1, step.Item2, step.Result)
+	}
+	return nil
+}
+
+func (s *session) count(args []string) error {
+	fs := flag.NewFlagSet("count", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	algorithm := fs.String("algo", "BFS", "")
+	positional, err := parseArgs(fs, args)
+	if err != nil || len(positional) != 1 {
+		return errUsage
+	}
+	element, err := s.resolve(positional[0])
+	if err != nil {
+		return err
+	}
+
+	result, err := s.searcher.Search(*algorithm, element, "All", 0, services.SearchOptions{})
+	if errors.Is(err, services.ErrUnknownAlgorithm) {
+		return fmt.Errorf("algoritma tidak dikenal: %s", *algorithm)
+	}
+	if err != nil {
+		return err
+	}
+	count := 0
+	if services.HasRecipe(result.Results) {
+		count = len(result.Results)
+	}
+	fmt.Fprintf(s.out, "%s: %d resep (%s, %d node dikunjungi, %.0f ms)\n", element, count, *algorithm, result.NodesVisited, result.ExecutionTime)
+	return nil
+}
+
+func (s *session) export(args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if s.last == nil {
+		return errors.New("belum ada hasil search untuk diekspor")
+	}
+
+	render := renderASCII
+	switch strings.ToLower(filepath.Ext(args[0])) {
+	case ".json":
+		render = renderJSON
+	case ".dot", ".gv":
+		render = renderDOT
+	}
+
+	file, err := os.Create(args[0])
+	if err != nil {
+		return err
+	}
+	if err := render(file, *s.last); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Fprintf(s.out, "Resep %s disimpan ke %s\n", s.last.Element, args[0])
+	return nil
+}
+
+// owned mengembalikan isi inventory sesuai urutan daftar elemen
+func (s *session) owned() []string {
+	var owned []string
+	for _, name := range s.names {
+		if s.inventory[name] {
+			owned = append(owned, name)
+		}
+	}
+	return owned
+}
+
+func (s *session) showInventory(args []string) error {
+	owned := s.owned()
+	fmt.Fprintf(s.out, "%d/%d elemen ditemukan:\n  %s\n", len(owned), len(s.names), strings.Join(owned, ", "))
+	return nil
+}
+
+func (s *session) discover(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	for _, arg := range args {
+		element, err := s.resolve(arg)
+		if err != nil {
+			return err
+		}
+		s.inventory[element] = true
+	}
+	return s.showInventory(nil)
+}
+
+func (s *session) forget(args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	for _, arg := range args {
+		element, err := s.resolve(arg)
+		if err != nil {
+			return err
+		}
+		delete(s.inventory, element)
+	}
+	return s.showInventory(nil)
+}
+
+func (s *session) craft(args []string) error {
+	if len(args) != 2 {
+		return errUsage
+	}
+	var items [2]string
+	for i, arg := range args {
+		element, err := s.resolve(arg)
+		if err != nil {
+			return err
+		}
+		if !s.inventory[element] {
+			return fmt.Errorf("%s belum ditemukan", element)
+		}
+		items[i] = element
+	}
+
+	products, err := s.repo.Products(items[0])
+	if err != nil {
+		return err
+	}
+	crafted := false
+	for _, product := range products {
+		if product.Partner != items[1] {
+			continue
+		}
+		crafted = true
+		if s.inventory[product.Result] {
+			fmt.Fprintf(s.out, "  %s + %s = %s (sudah ditemukan)\n", items[0], items[1], product.Result)
+			continue
+		}
+		s.inventory[product.Result] = true
+		fmt.Fprintf(s.out, "  %s + %s = %s (baru!)\n", items[0], items[1], product.Result)
+	}
+	if !crafted {
+		fmt.Fprintf(s.out, "  %s + %s tidak menghasilkan apa-apa\n", items[0], items[1])
+	}
+	return nil
+}
+
+func (s *session) hint(args []string) error {
+	if len(args) > 1 {
+		return errUsage
+	}
+	goal := ""
+	if len(args) == 1 {
+		var err error
+		if goal, err = s.resolve(args[0]); err != nil {
+			return err
+		}
+	}
+
+	hints, err := services.Hints(s.repo, s.owned(), goal, 5)
+	if err != nil {
+		return err
+	}
+	if len(hints) == 0 {
+		fmt.Fprintln(s.out, "  tidak ada kombinasi baru dari inventory")
+		return nil
+	}
+	for i, hint := range hints {
+		fmt.Fprintf(s.out, "%d. %s + %s = %s\n", i+1, hint.Item1, hint.Item2, strings.Join(hint.Results, ", "))
+		for _, reason := range hint.Reasons {
+			fmt.Fprintf(s.out, "     - %s\n", reason)
+		}
+	}
+	return nil
+}
+
+// splitLine memecah baris perintah per spasi, dengan tanda kutip untuk nama berspasi
+func splitLine(line string) []string {
+	var args []string
+	var current strings.Builder
+	inQuote, hasToken := false, false
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuote = !inQuote
+			hasToken = true
+		case r == ' ' && !inQuote:
+			if hasToken {
+				args = append(args, current.String())
+				current.Reset()
+				hasToken = false
+			}
+		default:
+			current.WriteRune(r)
+			hasToken = true
+		}
+	}
+	if hasToken {
+		args = append(args, current.String())
+	}
+	return args
+}
+
+// completer melengkapi nama perintah (kata pertama) atau nama elemen saat Tab ditekan
+func (s *session) completer(terminal *term.Terminal) func(line string, pos int, key rune) (string, int, bool) {
+	return func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+
+		// Cari awal kata yang sedang diketik, termasuk kata yang diawali tanda kutip
+		before := line[:pos]
+		start := strings.LastIndex(before, " ") + 1
+		if quote := strings.LastIndex(before, "\""); strings.Count(before, "\"")%2 == 1 {
+			start = quote
+		}
+		word := strings.TrimPrefix(before[start:], "\"")
+
+		candidates := s.names
+		if strings.TrimSpace(before[:start]) == "" {
+			candidates = make([]string, 0, len(replCommands))
+			for name := range replCommands {
+				candidates = append(candidates, name)
+			}
+			sort.Strings(candidates)
+		}
+
+		var matches []string
+		for _, candidate := range candidates {
+			if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(word)) {
+				matches = append(matches, candidate)
+			}
+		}
+
+		var completion string
+		switch len(matches) {
+		case 0:
+			return "", 0, false
+		case 1:
+			completion = matches[0] + " "
+			if strings.Contains(matches[0], " ") {
+				completion = "\"" + matches[0] + "\" "
+			}
+		default:
+			prefix := commonPrefix(matches)
+			if len(prefix) <= len(word) {
+				// Tidak ada yang bisa dilengkapi lagi: tampilkan pilihan
+				shown := matches
+				if len(shown) > 30 {
+					shown = append(shown[:30:30], fmt.Sprintf("... (%d lagi)", len(matches)-30))
+				}
+				fmt.Fprintf(terminal, "%s\n", strings.Join(shown, ", "))
+				return "", 0, false
+			}
+			completion = prefix
+			if strings.Contains(prefix, " ") || strings.HasPrefix(before[start:], "\"") {
+				completion = "\"" + prefix
+			}
+		}
+
+		newLine := line[:start] + completion + line[pos:]
+		return newLine, start + len(completion), true
+	}
+}
+
+// commonPrefix mengembalikan awalan bersama (tanpa memperhatikan huruf besar/kecil)
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, word := range words[1:] {
+		n := 0
+		for n < len(prefix) && n < len(word) && strings.EqualFold(prefix[n:n+1], word[n:n+1]) {
+			n++
+		}
+		prefix = prefix[:n]
+	}
+	return prefix
+}
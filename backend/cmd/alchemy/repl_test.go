@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"main/services"
+
+	"golang.org/x/term"
+)
+
+// newFixtureSession membuat sesi REPL di atas fixtureCSV
+func newFixtureSession(t *testing.T, out io.Writer) *session {
+	t.Helper()
+	repo, err := services.LoadCSVRepository(strings.NewReader(fixtureCSV), nil)
+	if err != nil {
+		t.Fatalf("memuat fixture: %v", err)
+	}
+	s, err := newSession(repo, out)
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+	return s
+}
+
+func TestSessionExecute(t *testing.T) {
+	export := filepath.Join(t.TempDir(), "brick.json")
+
+	tests := []struct {
+		name     string
+		lines    []string
+		want     string
+		wantQuit bool
+	}{
+		{name: "baris kosong", lines: []string{"   "}},
+		{name: "quit", lines: []string{"quit"}, wantQuit: true},
+		{name: "exit", lines: []string{"exit"}, wantQuit: true},
+		{name: "perintah tidak dikenal", lines: []string{"fly"}, want: "Perintah tidak dikenal: fly"},
+		{name: "help", lines: []string{"help"}, want: "recipes <elemen>"},
+		{name: "recipes tanpa memperhatikan huruf besar", lines: []string{"recipes brick"}, want: "  Brick = Mud + Fire\n  Brick = Stone + Fire\n"},
+		{name: "recipes elemen dasar", lines: []string{"recipes Fire"}, want: "Fire tidak punya resep (elemen dasar)"},
+		{name: "recipes tanpa argumen", lines: []string{"recipes"}, want: "Penggunaan: recipes <elemen>"},
+		{name: "recipes elemen tidak ada", lines: []string{"recipes Unicorn"}, want: "Unicorn: element not found"},
+		{name: "uses", lines: []string{"uses Mud"}, want: "  Mud + Fire = Brick\n"},
+		{name: "search", lines: []string{"search Brick -type All"}, want: "BFS: 2 resep"},
+		{name: "search dari inventory", lines: []string{"discover Stone", "search Brick -type All -inv"}, want: "Resep 1/2 (1 kombinasi baru)\n"},
+		{name: "search algoritma tidak dikenal", lines: []string{"search Brick -algo A*"}, want: "algoritma tidak dikenal: A*"},
+		{name: "search tanpa resep", lines: []string{"search Ghost"}, want: "Ghost: no recipe"},
+		{name: "count", lines: []string{"count Brick -algo DFS"}, want: "Brick: 2 resep (DFS"},
+		{name: "shortest", lines: []string{"shortest Earth Brick"}, want: "Earth -> Brick: 2 langkah"},
+		{name: "export tanpa search", lines: []string{"export " + export}, want: "belum ada hasil search untuk diekspor"},
+		{name: "export setelah search", lines: []string{"search Brick", "export " + export}, want: "Resep Brick disimpan ke " + export},
+		{name: "craft", lines: []string{"craft Water Earth"}, want: "  Water + Earth = Mud (baru!)\n"},
+		{name: "craft dua kali", lines: []string{"craft Water Earth", "craft Earth Water"}, want: "  Earth + Water = Mud (sudah ditemukan)\n"},
+		{name: "craft tanpa hasil", lines: []string{"craft Air Air"}, want: "  Air + Air tidak menghasilkan apa-apa\n"},
+		{name: "craft elemen belum ditemukan", lines: []string{"craft Mud Fire"}, want: "Mud belum ditemukan"},
+		{name: "hint", lines: []string{"hint"}, want: "1. Earth + Fire = Lava\n"},
+		{name: "hint menuju tujuan", lines: []string{"hint Stone"}, want: "Earth + Fire = Lava"},
+		{name: "hint tanpa kombinasi baru", lines: []string{"discover Mud Lava Stone Brick", "hint"}, want: "  tidak ada kombinasi baru dari inventory\n"},
+		{name: "hint terlalu banyak argumen", lines: []string{"hint Stone Brick"}, want: "Penggunaan: hint [tujuan]"},
+		{name: "discover", lines: []string{"discover mud"}, want: "5/9 elemen ditemukan:\n  Air, Earth, Fire, Mud, Water\n"},
+		{name: "forget", lines: []string{"forget Air"}, want: "3/9 elemen ditemukan:\n  Earth, Fire, Water\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out strings.Builder
+			s := newFixtureSession(t, &out)
+
+			quit := false
+			for _, line := range tt.lines {
+				quit = !s.execute(line)
+			}
+			if quit != tt.wantQuit {
+				t.Errorf("sesi selesai = %v, ingin %v", quit, tt.wantQuit)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("keluaran tidak berisi %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
+
+// failingRepository gagal setiap kali resep sebuah elemen dibaca
+type failingRepository struct {
+	services.RecipeRepository
+}
+
+func (failingRepository) Recipes(string) ([]services.Combination, error) {
+	return nil, errors.New("dataset tidak bisa dibaca")
+}
+
+func TestSessionSearchRepositoryError(t *testing.T) {
+	for _, line := range []string{"search Brick", "count Brick"} {
+		t.Run(line, func(t *testing.T) {
+			var out strings.Builder
+			s := newFixtureSession(t, &out)
+			s.searcher = services.NewSearcher(failingRepository{s.repo})
+
+			s.execute(line)
+			if !strings.Contains(out.String(), "dataset tidak bisa dibaca") {
+				t.Errorf("keluaran tidak berisi error dataset:\n%s", out.String())
+			}
+			if strings.Contains(out.String(), "algoritma tidak dikenal") {
+				t.Errorf("error dataset dilaporkan sebagai algoritma tidak dikenal:\n%s", out.String())
+			}
+		})
+	}
+}
+
+func TestSplitLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want []string
+	}{
+		{line: "", want: nil},
+		{line: "  recipes   Brick ", want: []string{"recipes", "Brick"}},
+		{line: `craft "Acid rain" Water`, want: []string{"craft", "Acid rain", "Water"}},
+		{line: `discover ""`, want: []string{"discover", ""}},
+	}
+
+	for _, tt := range tests {
+		if got := splitLine(tt.line); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitLine(%q) = %q, ingin %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestCompleter(t *testing.T) {
+	repo := services.NewMemoryRepository([]services.ElementRow{
+		{Element: "Air"},
+		{Element: "Water"},
+		{Element: "Acid", Item1: "Air", Item2: "Water"},
+		{Element: "Acid rain", Item1: "Acid", Item2: "Water"},
+	}, nil)
+	s, err := newSession(repo, io.Discard)
+	if err != nil {
+		t.Fatalf("newSession: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		line     string
+		key      rune
+		wantLine string
+		wantPos  int
+		wantOK   bool
+		wantList string
+	}{
+		{name: "bukan tab", line: "rec", key: 'a'},
+		{name: "nama perintah", line: "rec", key: '\t', wantLine: "recipes ", wantPos: 8, wantOK: true},
+		{name: "nama elemen", line: "uses wa", key: '\t', wantLine: "uses Water ", wantPos: 11, wantOK: true},
+		{name: "awalan bersama", line: "uses ac", key: '\t', wantLine: "uses Acid", wantPos: 9, wantOK: true},
+		{name: "nama berspasi dikutip", line: `uses "acid r`, key: '\t', wantLine: `uses "Acid rain" `, wantPos: 17, wantOK: true},
+		{name: "beberapa pilihan", line: "uses acid", key: '\t', wantList: "Acid, Acid rain"},
+		{name: "tidak ada yang cocok", line: "uses fire", key: '\t'},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var screen bytes.Buffer
+			terminal := term.NewTerminal(struct {
+				io.Reader
+				io.Writer
+			}{strings.NewReader(""), &screen}, "")
+
+			line, pos, ok := s.completer(terminal)(tt.line, len(tt.line), tt.key)
+			if line != tt.wantLine || pos != tt.wantPos || ok != tt.wantOK {
+				t.Errorf("completer(%q) = (%q, %d, %v), ingin (%q, %d, %v)",
+					tt.line, line, pos, ok, tt.wantLine, tt.wantPos, tt.wantOK)
+			}
+			if !strings.Contains(screen.String(), tt.wantList) {
+				t.Errorf("pilihan yang ditampilkan %q tidak berisi %q", screen.String(), tt.wantList)
+			}
+		})
+	}
+}
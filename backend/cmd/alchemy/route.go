@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"main/services"
+)
+
+// runRoute menjalankan perintah "route": urutan kombinasi untuk membuka semua elemen
+func runRoute(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("route", flag.ContinueOnError)
+	format := fs.String("format", "ascii", "format keluaran: ascii atau json")
+	summary := fs.Bool("summary", false, "hanya cetak ringkasan per tier")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Penggunaan: alchemy route [flag]\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 0 || (*format != "ascii" && *format != "json") {
+		fs.Usage()
+		return errUsage
+	}
+
+	memory, err := services.LoadMemoryRepository(repo)
+	if err != nil {
+		return err
+	}
+	plan, err := services.PlanFullGame(memory)
+	if err != nil {
+		return err
+	}
+
+	if *format == "json" {
+		if *summary {
+			plan.Steps = []services.FullPlanStep{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(plan)
+	}
+
+	var b strings.Builder
+	if !*summary {
+		tier := 0
+		for i, step := range plan.Steps {
+			if step.Tier != tier {
+				tier = step.Tier
+				fmt.Fprintf(&b, "-- Tier %d --\n", tier)
+			}
+			fmt.Fprintf(&b, "%4d. %s + %s = %s\n", i+1, step.Item1, step.Item2, strings.Join(step.Discovers, ", "))
+		}
+		b.WriteString("\n")
+	}
+	fmt.Fprintf(&b, "%-5s %8s %8s %8s %8s\n", "Tier", "Langkah", "Total", "Elemen", "Total")
+	for _, checkpoint := range plan.Checkpoints {
+		fmt.Fprintf(&b, "%-5d %8d %8d %8d %8d\n", checkpoint.Tier, checkpoint.Steps, checkpoint.TotalSteps, checkpoint.Elements, checkpoint.TotalElements)
+	}
+	fmt.Fprintf(&b, "\n%d kombinasi membuka %d elemen (%d elemen dasar)", len(plan.Steps), plan.Discovered, plan.Basic)
+	if len(plan.Unreachable) > 0 {
+		fmt.Fprintf(&b, ", %d elemen tidak terjangkau", len(plan.Unreachable))
+	}
+	b.WriteString("\n")
+	_, err = io.WriteString(out, b.String())
+	return err
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"main/services"
+)
+
+// runSearch menjalankan perintah "search <elemen>"
+func runSearch(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("search", flag.ContinueOnError)
+	algorithm := fs.String("algo", "BFS", "algoritma pencarian: BFS, DFS atau Bidirectional")
+	recipeType := fs.String("type", "One", "tipe resep: One, Limit atau All")
+	maxRecipes := fs.Int("max", 5, "jumlah maksimal resep untuk -type Limit")
+	format := fs.String("format", "ascii", "format keluaran: ascii, json atau dot")
+	owned := fs.String("owned", "", "elemen yang sudah dimiliki, dipisah koma (contoh: \"Brick,Fire\")")
+	exclude := fs.String("exclude", "", "elemen atau kombinasi yang tidak boleh dipakai, dipisah koma (contoh: \"Clay,Earth + Life\")")
+	require := fs.String("require", "", "elemen yang wajib muncul di resep, dipisah koma")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Penggunaan: alchemy search <elemen> [flag]\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return errUsage
+	}
+	render, ok := renderers[*format]
+	if !ok {
+		fmt.Fprintf(fs.Output(), "Format tidak dikenal: %s\n", *format)
+		return errUsage
+	}
+
+	element, err := resolveElement(searcher, positional[0])
+	if err != nil {
+		return err
+	}
+	result, err := searcher.Search(*algorithm, element, *recipeType, *maxRecipes, services.SearchOptions{
+		Owned:   splitList(*owned),
+		Exclude: splitList(*exclude),
+		Require: splitList(*require),
+	})
+	if errors.Is(err, services.ErrUnknownAlgorithm) {
+		fmt.Fprintf(fs.Output(), "Algoritma tidak dikenal: %s\n", *algorithm)
+		return errUsage
+	}
+	if err != nil {
+		return err
+	}
+	if err := statusError(element, result); err != nil {
+		return err
+	}
+	if result.Status == services.StatusBasicElement && *format != "json" {
+		_, err := fmt.Fprintf(out, "%s: %s\n", element, result.Message)
+		return err
+	}
+
+	return render(out, newSearchOutput(element, *algorithm, result))
+}
+
+// statusError mengubah status pencarian yang gagal menjadi error dengan kode keluar 1
+func statusError(element string, result *services.SearchResult) error {
+	switch result.Status {
+	case services.StatusElementNotFound:
+		return fmt.Errorf("%s: %w", element, services.ErrElementNotFound)
+	case services.StatusNoRecipe:
+		return fmt.Errorf("%s: %w", element, services.ErrNoRecipe)
+	}
+	return nil
+}
+
+// splitList memecah daftar elemen yang dipisah koma, mengabaikan item kosong
+func splitList(list string) []string {
+	var items []string
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
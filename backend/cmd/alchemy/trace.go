@@ -0,0 +1,366 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"main/services"
+)
+
+// runTrace menjalankan perintah "trace <elemen>": merekam setiap langkah traversal,
+// lalu mencetaknya, menyimpannya sebagai JSON, atau membandingkannya dengan algoritma lain
+func runTrace(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("trace", flag.ContinueOnError)
+	algorithm := fs.String("algo", "BFS", "algoritma pencarian: BFS, DFS atau Bidirectional")
+	versus := fs.String("vs", "", "algoritma pembanding; cetak perbedaan kedua traversal")
+	recipeType := fs.String("type", "One", "tipe resep: One, Limit atau All")
+	maxRecipes := fs.Int("max", 5, "jumlah maksimal resep untuk -type Limit")
+	owned := fs.String("owned", "", "elemen yang sudah dimiliki, dipisah koma")
+	exclude := fs.String("exclude", "", "elemen atau kombinasi yang tidak boleh dipakai, dipisah koma")
+	require := fs.String("require", "", "elemen yang wajib muncul di resep, dipisah koma")
+	kinds := fs.String("kinds", "", "jenis langkah yang dicetak, dipisah koma (default: semua)")
+	format := fs.String("format", "ascii", "format keluaran: ascii atau json")
+	output := fs.String("o", "", "simpan trace sebagai JSON ke file ini")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Penggunaan: alchemy trace <elemen> [flag]\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 || (*format != "ascii" && *format != "json") {
+		fs.Usage()
+		return errUsage
+	}
+	filter, err := parseKinds(fs, *kinds)
+	if err != nil {
+		return err
+	}
+
+	element, err := resolveElement(searcher, positional[0])
+	if err != nil {
+		return err
+	}
+	record := func(algorithm string) (*services.Trace, error) {
+		trace := services.NewTrace(0) // Simpan semua langkah
+		_, err := searcher.Search(algorithm, element, *recipeType, *maxRecipes, services.SearchOptions{
+			Owned:   splitList(*owned),
+			Exclude: splitList(*exclude),
+			Require: splitList(*require),
+			Trace:   trace,
+		})
+		if errors.Is(err, services.ErrUnknownAlgorithm) {
+			fmt.Fprintf(fs.Output(), "Algoritma tidak dikenal: %s\n", algorithm)
+			return nil, errUsage
+		}
+		if err != nil {
+			return nil, err
+		}
+		return trace, nil
+	}
+
+	trace, err := record(*algorithm)
+	if err != nil {
+		return err
+	}
+	if *output != "" {
+		if err := saveTrace(*output, trace); err != nil {
+			return err
+		}
+	}
+	if *versus != "" {
+		other, err := record(*versus)
+		if err != nil {
+			return err
+		}
+		return renderComparison(out, *format, compareTraces(trace, other, trace.Algorithm, other.Algorithm))
+	}
+	if *output != "" && *format == "ascii" {
+		_, err := fmt.Fprintf(out, "Trace disimpan di %s (%d langkah)\n", *output, trace.Len())
+		return err
+	}
+	return renderTrace(out, *format, trace, filter)
+}
+
+// runReplay menjalankan perintah "replay <file> [file]": mencetak trace yang disimpan
+// oleh "trace -o" atau diunduh dari GET /api/traces/:id, atau membandingkan dua trace
+func runReplay(repo services.RecipeRepository, searcher *services.Searcher, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("replay", flag.ContinueOnError)
+	kinds := fs.String("kinds", "", "jenis langkah yang dicetak, dipisah koma (default: semua)")
+	format := fs.String("format", "ascii", "format keluaran: ascii atau json")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Penggunaan: alchemy replay <trace.json> [trace-pembanding.json] [flag]\n")
+		fs.PrintDefaults()
+	}
+
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 1 || len(positional) > 2 || (*format != "ascii" && *format != "json") {
+		fs.Usage()
+		return errUsage
+	}
+	filter, err := parseKinds(fs, *kinds)
+	if err != nil {
+		return err
+	}
+
+	traces := make([]*services.Trace, len(positional))
+	for i, path := range positional {
+		if traces[i], err = loadTrace(path); err != nil {
+			return err
+		}
+	}
+	if len(traces) == 1 {
+		return renderTrace(out, *format, traces[0], filter)
+	}
+
+	labels := []string{traces[0].Algorithm, traces[1].Algorithm}
+	if labels[0] == labels[1] {
+		labels = []string{filepath.Base(positional[0]), filepath.Base(positional[1])}
+	}
+	return renderComparison(out, *format, compareTraces(traces[0], traces[1], labels[0], labels[1]))
+}
+
+// parseKinds memeriksa daftar jenis langkah yang dipisah koma
+func parseKinds(fs *flag.FlagSet, list string) ([]services.StepKind, error) {
+	var kinds []services.StepKind
+	for _, item := range splitList(list) {
+		kind := services.StepKind(item)
+		if !slices.Contains(services.StepKinds, kind) {
+			fmt.Fprintf(fs.Output(), "Jenis langkah tidak dikenal: %s (pilihan: %v)\n", item, services.StepKinds)
+			return nil, errUsage
+		}
+		kinds = append(kinds, kind)
+	}
+	return kinds, nil
+}
+
+// saveTrace menulis trace sebagai JSON ke file
+func saveTrace(path string, trace *services.Trace) error {
+	data, err := json.Marshal(trace)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// loadTrace membaca trace dari file JSON. File boleh berisi trace saja atau envelope
+// lengkap dari GET /api/traces/:id.
+func loadTrace(path string) (*services.Trace, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if json.Unmarshal(data, &envelope) == nil && len(envelope.Data) > 0 {
+		data = envelope.Data
+	}
+	trace := &services.Trace{}
+	if err := json.Unmarshal(data, trace); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return trace, nil
+}
+
+// renderTrace mencetak langkah trace dengan jenis di kinds (semua jika kosong)
+func renderTrace(w io.Writer, format string, trace *services.Trace, kinds []services.StepKind) error {
+	steps := make([]services.TraceStep, 0, trace.Len())
+	for _, step := range trace.Steps() {
+		if len(kinds) == 0 || slices.Contains(kinds, step.Kind) {
+			steps = append(steps, step)
+		}
+	}
+
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(struct {
+			services.TraceSummary
+			Steps []services.TraceStep `json:"steps"`
+		}{trace.TraceSummary, steps})
+	}
+
+	var b strings.Builder
+	for _, step := range steps {
+		b.WriteString(formatStep(step) + "\n")
+	}
+	fmt.Fprintf(&b, "\n%s %s: %d langkah, %d node dikunjungi, %d resep (%s)",
+		trace.Algorithm, trace.Element, trace.Events, trace.NodesVisited, trace.Recipes, trace.Status)
+	if trace.Truncated {
+		fmt.Fprintf(&b, ", hanya %d langkah yang tersimpan", trace.Len())
+	}
+	b.WriteString("\n")
+	if trace.Error != "" {
+		fmt.Fprintf(&b, "Gagal menyimpan langkah: %s\n", trace.Error)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// formatStep menulis satu langkah dalam satu baris, menjorok sesuai kedalamannya
+func formatStep(step services.TraceStep) string {
+	return fmt.Sprintf("%6d  %-6s  %s%s", step.Seq, step.Kind, strings.Repeat("  ", step.Depth), describeStep(step.Step))
+}
+
+// describeStep menulis elemen langkah beserta kombinasi atau resepnya
+func describeStep(step services.Step) string {
+	switch step.Kind {
+	case services.StepExpand:
+		return step.Element + " = " + step.Item1 + " + " + step.Item2
+	case services.StepRecipe, services.StepDedup:
+		return step.Element + ": " + strings.Join(step.Recipe, ", ")
+	}
+	return step.Element
+}
+
+// traceComparison adalah perbedaan traversal dua trace
+type traceComparison struct {
+	Labels       [2]string                    `json:"labels"`
+	Events       [2]int                       `json:"events"`
+	NodesVisited [2]int                       `json:"nodesVisited"`
+	Recipes      [2]int                       `json:"recipes"`
+	Counts       map[services.StepKind][2]int `json:"counts"`
+	Diverge      int                          `json:"diverge"` // Nomor langkah pertama yang berbeda, 0 jika sama persis
+	Steps        [2]*services.TraceStep       `json:"steps"`   // Langkah masing-masing trace di Diverge
+	OnlyVisited  [2][]string                  `json:"onlyVisited"`
+	OnlyRecipes  [2][]string                  `json:"onlyRecipes"`
+	Truncated    bool                         `json:"truncated,omitempty"` // Salah satu trace tidak lengkap
+}
+
+// compareTraces membandingkan dua trace: jumlah langkah per jenis, langkah pertama yang
+// berbeda, elemen yang hanya dikunjungi salah satu, dan resep yang hanya ditemukan salah satu
+func compareTraces(a, b *services.Trace, labelA, labelB string) traceComparison {
+	comparison := traceComparison{
+		Labels:       [2]string{labelA, labelB},
+		Events:       [2]int{a.Events, b.Events},
+		NodesVisited: [2]int{a.NodesVisited, b.NodesVisited},
+		Recipes:      [2]int{a.Recipes, b.Recipes},
+		Counts:       map[services.StepKind][2]int{},
+		Truncated:    a.Truncated || b.Truncated,
+	}
+	for _, kind := range services.StepKinds {
+		comparison.Counts[kind] = [2]int{a.Counts[kind], b.Counts[kind]}
+	}
+
+	steps := [2][]services.TraceStep{a.Steps(), b.Steps()}
+	for i := 0; i < max(len(steps[0]), len(steps[1])); i++ {
+		var left, right *services.TraceStep
+		if i < len(steps[0]) {
+			left = &steps[0][i]
+		}
+		if i < len(steps[1]) {
+			right = &steps[1][i]
+		}
+		if left == nil || right == nil || !sameStep(left.Step, right.Step) {
+			comparison.Diverge = i + 1
+			comparison.Steps = [2]*services.TraceStep{left, right}
+			break
+		}
+	}
+
+	visited := [2]map[string]bool{{}, {}}
+	recipes := [2]map[string]bool{{}, {}}
+	for side := range steps {
+		for _, step := range steps[side] {
+			switch step.Kind {
+			case services.StepVisit:
+				visited[side][step.Element] = true
+			case services.StepRecipe:
+				recipes[side][strings.Join(step.Recipe, ", ")] = true
+			}
+		}
+	}
+	for side := range steps {
+		comparison.OnlyVisited[side] = onlyIn(visited[side], visited[1-side])
+		comparison.OnlyRecipes[side] = onlyIn(recipes[side], recipes[1-side])
+	}
+	return comparison
+}
+
+// sameStep membandingkan dua langkah tanpa jumlah node yang sudah dikunjungi
+func sameStep(a, b services.Step) bool {
+	return a.Kind == b.Kind && a.Element == b.Element && a.Item1 == b.Item1 && a.Item2 == b.Item2 &&
+		a.Depth == b.Depth && slices.Equal(a.Recipe, b.Recipe)
+}
+
+// onlyIn mengembalikan isi set yang tidak ada di other, diurutkan
+func onlyIn(set, other map[string]bool) []string {
+	items := []string{}
+	for item := range set {
+		if !other[item] {
+			items = append(items, item)
+		}
+	}
+	slices.Sort(items)
+	return items
+}
+
+// renderComparison mencetak perbandingan dua trace
+func renderComparison(w io.Writer, format string, comparison traceComparison) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(comparison)
+	}
+
+	labels := comparison.Labels
+	var b strings.Builder
+	row := func(name string, values [2]int) {
+		fmt.Fprintf(&b, "%-16s %14d %14d\n", name, values[0], values[1])
+	}
+	fmt.Fprintf(&b, "%-16s %14s %14s\n", "", labels[0], labels[1])
+	row("Langkah", comparison.Events)
+	for _, kind := range services.StepKinds {
+		row("  "+string(kind), comparison.Counts[kind])
+	}
+	row("Node dikunjungi", comparison.NodesVisited)
+	row("Resep", comparison.Recipes)
+
+	b.WriteString("\n")
+	if comparison.Diverge == 0 {
+		b.WriteString("Kedua traversal sama persis\n")
+	} else {
+		fmt.Fprintf(&b, "Langkah pertama yang berbeda: #%d\n", comparison.Diverge)
+		for side, step := range comparison.Steps {
+			text := "(trace sudah selesai)"
+			if step != nil {
+				text = fmt.Sprintf("%s %s (kedalaman %d)", step.Kind, describeStep(step.Step), step.Depth)
+			}
+			fmt.Fprintf(&b, "  %-14s %s\n", labels[side]+":", text)
+		}
+	}
+
+	for side := range labels {
+		if list := comparison.OnlyVisited[side]; len(list) > 0 {
+			fmt.Fprintf(&b, "\nHanya dikunjungi %s (%d): %s\n", labels[side], len(list), strings.Join(list, ", "))
+		}
+	}
+	for side := range labels {
+		if list := comparison.OnlyRecipes[side]; len(list) > 0 {
+			fmt.Fprintf(&b, "\nResep hanya ditemukan %s (%d):\n", labels[side], len(list))
+			for _, recipe := range list {
+				fmt.Fprintf(&b, "  %s\n", recipe)
+			}
+		}
+	}
+	if comparison.Truncated {
+		b.WriteString("\nSalah satu trace tidak lengkap, perbandingan hanya memakai langkah yang tersimpan\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,195 @@
+// File ini berisi controller pencarian batch: banyak elemen dalam satu request,
+// dijalankan bersamaan dengan batas waktu bersama.
+
+package controllers
+
+import (
+	"context"       // Untuk batas waktu bersama
+	"main/services" // Import katalog dataset dan penyimpanan profil
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+	"sync"          // Untuk worker pencarian
+	"time"          // Untuk mengukur budget dan lama batch
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// Nilai default request batch
+const (
+	defaultBatchConcurrency = 4
+	defaultBatchBudget      = 10 * time.Second
+)
+
+// batchRequest adalah body POST /api/search/batch. Setiap item di searches memakai
+// aturan yang sama dengan POST /api/search, tetapi diperiksa sendiri-sendiri sehingga
+// item yang salah tidak menggagalkan item lain.
+type batchRequest struct {
+	Searches    []searchRequest `json:"searches" binding:"required,min=1,max=100"`
+	Concurrency int             `json:"concurrency" binding:"omitempty,min=1,max=8"`  // Jumlah pencarian yang berjalan bersamaan
+	BudgetMs    int             `json:"budgetMs" binding:"omitempty,min=1,max=60000"` // Batas waktu seluruh batch dalam milidetik
+}
+
+// batchItem adalah hasil satu pencarian di batch, dengan status, code dan data yang
+// sama seperti envelope POST /api/search
+type batchItem struct {
+	Type    string             `json:"type,omitempty"` // "result", hanya di NDJSON
+	Index   int                `json:"index"`          // Posisi pencarian di searches
+	Element string             `json:"element"`
+	Status  string             `json:"status"`
+	Code    string             `json:"code"`
+	Message string             `json:"message"`
+	Errors  []utils.FieldError `json:"errors,omitempty"`
+	Data    gin.H              `json:"data,omitempty"`
+
+	nodesVisited int
+	cutOff       bool // Dihentikan di tengah pencarian
+}
+
+// batchSummary merangkum satu batch
+type batchSummary struct {
+	Type         string  `json:"type,omitempty"` // "summary", hanya di NDJSON
+	Total        int     `json:"total"`
+	Succeeded    int     `json:"succeeded"`
+	Failed       int     `json:"failed"`
+	Skipped      int     `json:"skipped"` // Belum dimulai saat budget habis
+	CutOff       int     `json:"cutOff"`  // Sedang berjalan saat budget habis, lalu dihentikan
+	NodesVisited int     `json:"nodesVisited"`
+	ElapsedMs    float64 `json:"elapsedMs"`
+}
+
+// SearchBatch membuat handler POST /api/search/batch. Hasil dikirim sekaligus dalam
+// envelope, atau satu per baris begitu selesai jika client meminta NDJSON lewat
+// header Accept: application/x-ndjson. Baris terakhir NDJSON adalah ringkasan.
+func SearchBatch(catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody batchRequest
+		if !utils.BindJSON(c, &requestBody, utils.Strict(c)) {
+			return // Error 400 sudah dikirim beserta field yang salah
+		}
+
+		if utils.WantsNDJSON(c) {
+			write := utils.StartNDJSON(c)
+			summary, err := runBatch(c.Request.Context(), catalog, players, traces, requestBody, func(item batchItem) error {
+				item.Type = "result"
+				return write(item) // Jika gagal, client sudah putus dan batch dihentikan
+			})
+			if err != nil {
+				return
+			}
+			summary.Type = "summary"
+			write(summary)
+			return
+		}
+
+		results := make([]batchItem, len(requestBody.Searches))
+		summary, _ := runBatch(c.Request.Context(), catalog, players, traces, requestBody, func(item batchItem) error {
+			results[item.Index] = item // Urutan sama dengan searches
+			return nil
+		})
+		utils.Send(c, http.StatusOK, utils.CodeOK, "Batch finished", gin.H{
+			"results": results,
+			"summary": summary,
+		})
+	}
+}
+
+// runBatch menjalankan semua pencarian dengan sejumlah worker. Saat budget habis atau
+// client putus, pencarian yang belum dimulai dilewati dan pencarian yang sedang berjalan
+// dihentikan, keduanya dengan code BUDGET_EXHAUSTED. emit dipanggil dari satu goroutine
+// untuk setiap item begitu selesai; error dari emit menghentikan batch dan dikembalikan.
+func runBatch(ctx context.Context, catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore, request batchRequest, emit func(batchItem) error) (batchSummary, error) {
+	start := time.Now()
+	budget := defaultBatchBudget
+	if request.BudgetMs > 0 {
+		budget = time.Duration(request.BudgetMs) * time.Millisecond
+	}
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+	concurrency := defaultBatchConcurrency
+	if request.Concurrency > 0 {
+		concurrency = request.Concurrency
+	}
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := range request.Searches {
+			jobs <- i
+		}
+	}()
+
+	items := make(chan batchItem)
+	var workers sync.WaitGroup
+	for range min(concurrency, len(request.Searches)) {
+		workers.Add(1)
+		go func() {
+			defer workers.Done()
+			for i := range jobs {
+				items <- runBatchItem(ctx, catalog, players, traces, i, request.Searches[i])
+			}
+		}()
+	}
+	go func() {
+		workers.Wait()
+		close(items)
+	}()
+
+	summary := batchSummary{Total: len(request.Searches)}
+	var emitErr error
+	for item := range items {
+		switch {
+		case item.cutOff:
+			summary.CutOff++
+		case item.Code == utils.CodeBudgetExhausted:
+			summary.Skipped++
+		case item.Status == utils.StatusSuccess:
+			summary.Succeeded++
+		default:
+			summary.Failed++
+		}
+		summary.NodesVisited += item.nodesVisited
+		if emitErr != nil {
+			continue // Tunggu worker selesai tanpa mengirim apa-apa lagi
+		}
+		if emitErr = emit(item); emitErr != nil {
+			cancel() // Hentikan pencarian yang masih berjalan
+		}
+	}
+	summary.ElapsedMs = float64(time.Since(start).Microseconds()) / 1000
+	return summary, emitErr
+}
+
+// runBatchItem memeriksa dan menjalankan satu pencarian batch
+func runBatchItem(ctx context.Context, catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore, index int, search searchRequest) batchItem {
+	item := batchItem{Index: index, Element: search.ElementName}
+	if ctx.Err() != nil {
+		return item.withOutcome(failedSearch(http.StatusServiceUnavailable, utils.CodeBudgetExhausted, "Batch budget exhausted before the search started"))
+	}
+	if errs := utils.ValidationErrors(&search); len(errs) > 0 {
+		return item.withOutcome(failedSearch(http.StatusBadRequest, utils.CodeValidationFailed, "Request validation failed", errs...))
+	}
+
+	prepared, failure := prepareSearch(catalog, players, traces, search)
+	if failure != nil {
+		return item.withOutcome(failure)
+	}
+	outcome := prepared.run(ctx)
+	if outcome == nil {
+		item.cutOff = true
+		return item.withOutcome(failedSearch(http.StatusServiceUnavailable, utils.CodeBudgetExhausted, "Batch budget exhausted while the search was running"))
+	}
+	if nodes, ok := outcome.data["nodesVisited"].(int); ok {
+		item.nodesVisited = nodes
+	}
+	return item.withOutcome(outcome)
+}
+
+// withOutcome mengisi item dengan hasil pencarian
+func (item batchItem) withOutcome(outcome *searchOutcome) batchItem {
+	item.Status = outcome.status()
+	item.Code = outcome.code
+	item.Message = outcome.message
+	item.Errors = outcome.errs
+	item.Data = outcome.data
+	return item
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main/services"
+	"main/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// slowRepository memperlambat setiap pembacaan resep supaya budget batch bisa habis
+// di tengah pencarian tanpa bergantung pada ukuran dataset
+type slowRepository struct {
+	services.RecipeRepository
+	delay time.Duration
+}
+
+func (r slowRepository) Recipes(element string) ([]services.Combination, error) {
+	time.Sleep(r.delay)
+	return r.RecipeRepository.Recipes(element)
+}
+
+// postBatch mengirim body ke POST /api/search/batch dengan header Accept opsional
+func postBatch(t *testing.T, handler http.Handler, body any, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("encode body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/api/search/batch", bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// batchResponse adalah data envelope POST /api/search/batch
+type batchResponse struct {
+	Code string `json:"code"`
+	Data struct {
+		Results []batchItem  `json:"results"`
+		Summary batchSummary `json:"summary"`
+	} `json:"data"`
+}
+
+func newBatchRouter(catalog *services.Catalog) *gin.Engine {
+	router := gin.New()
+	router.POST("/api/search/batch", SearchBatch(catalog, nil, nil))
+	return router
+}
+
+func TestSearchBatch(t *testing.T) {
+	router := newBatchRouter(newFixtureCatalog())
+	rec := postBatch(t, router, gin.H{"searches": []gin.H{
+		{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"},
+		{"elementName": "Unicorn", "algorithm": "DFS", "recipeType": "One"},
+		{"elementName": "Brick", "algorithm": "A*", "recipeType": "One"},
+		{"elementName": "Ghost", "algorithm": "Bidirectional", "recipeType": "One"},
+	}}, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status HTTP = %d, ingin 200\n%s", rec.Code, rec.Body.String())
+	}
+
+	var response batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("respons bukan JSON yang valid: %v", err)
+	}
+	wantCodes := []string{"OK", "ELEMENT_NOT_FOUND", utils.CodeValidationFailed, "NO_RECIPE"}
+	if len(response.Data.Results) != len(wantCodes) {
+		t.Fatalf("jumlah hasil = %d, ingin %d", len(response.Data.Results), len(wantCodes))
+	}
+	for i, item := range response.Data.Results {
+		if item.Index != i || item.Code != wantCodes[i] {
+			t.Errorf("hasil %d = index %d code %s, ingin index %d code %s", i, item.Index, item.Code, i, wantCodes[i])
+		}
+		if item.Type != "" {
+			t.Errorf("hasil %d: type = %q, harus kosong di luar NDJSON", i, item.Type)
+		}
+	}
+	if summary := response.Data.Summary; summary.Total != 4 || summary.Succeeded != 1 || summary.Failed != 3 || summary.Skipped != 0 {
+		t.Errorf("summary = %+v, ingin total 4, succeeded 1, failed 3, skipped 0", summary)
+	}
+}
+
+func TestSearchBatchValidation(t *testing.T) {
+	router := newBatchRouter(newFixtureCatalog())
+	tests := []struct {
+		name string
+		body gin.H
+	}{
+		{name: "tanpa pencarian", body: gin.H{"searches": []gin.H{}}},
+		{name: "concurrency terlalu besar", body: gin.H{"searches": []gin.H{{"elementName": "Brick", "algorithm": "BFS", "recipeType": "One"}}, "concurrency": 9}},
+		{name: "budget negatif", body: gin.H{"searches": []gin.H{{"elementName": "Brick", "algorithm": "BFS", "recipeType": "One"}}, "budgetMs": -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postBatch(t, router, tt.body, "")
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("status HTTP = %d, ingin 400\n%s", rec.Code, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSearchBatchBudget(t *testing.T) {
+	catalog := services.NewCatalog()
+	catalog.Add(services.DefaultDataset, slowRepository{
+		RecipeRepository: services.NewMemoryRepository(fixtureRows, nil),
+		delay:            20 * time.Millisecond,
+	})
+	router := newBatchRouter(catalog)
+
+	// Satu worker: pencarian pertama dihentikan di tengah jalan, sisanya tidak dimulai
+	search := gin.H{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"}
+	rec := postBatch(t, router, gin.H{
+		"searches":    []gin.H{search, search, search},
+		"concurrency": 1,
+		"budgetMs":    10,
+	}, "")
+
+	var response batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("respons bukan JSON yang valid: %v", err)
+	}
+	if summary := response.Data.Summary; summary.Total != 3 || summary.CutOff != 1 || summary.Skipped != 2 || summary.Succeeded != 0 {
+		t.Fatalf("summary = %+v, ingin 1 dihentikan dan 2 dilewati", summary)
+	}
+	for _, item := range response.Data.Results {
+		if item.Code != utils.CodeBudgetExhausted || item.Status != utils.StatusError {
+			t.Errorf("hasil %d = %s/%s, ingin %s/%s", item.Index, item.Status, item.Code, utils.StatusError, utils.CodeBudgetExhausted)
+		}
+	}
+	if message := response.Data.Results[0].Message; !strings.Contains(message, "while the search was running") {
+		t.Errorf("pesan hasil pertama = %q, ingin pencarian yang dihentikan", message)
+	}
+}
+
+func TestSearchBatchNDJSON(t *testing.T) {
+	router := newBatchRouter(newFixtureCatalog())
+	rec := postBatch(t, router, gin.H{"searches": []gin.H{
+		{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"},
+		{"elementName": "Stone", "algorithm": "DFS", "recipeType": "One"},
+		{"elementName": "Unicorn", "algorithm": "BFS", "recipeType": "One"},
+	}}, utils.NDJSONContentType)
+
+	if contentType := rec.Header().Get("Content-Type"); contentType != utils.NDJSONContentType {
+		t.Fatalf("Content-Type = %q, ingin %q", contentType, utils.NDJSONContentType)
+	}
+
+	// Hasil dikirim dalam urutan selesai, lalu satu baris ringkasan
+	lines := readNDJSON(t, rec)
+	if len(lines) != 4 {
+		t.Fatalf("jumlah baris = %d, ingin 3 hasil dan 1 ringkasan\n%s", len(lines), rec.Body.String())
+	}
+
+	seen := make(map[float64]bool)
+	for _, line := range lines[:3] {
+		if line["type"] != "result" {
+			t.Errorf("type = %v, ingin result", line["type"])
+		}
+		index, _ := line["index"].(float64)
+		seen[index] = true
+	}
+	if len(seen) != 3 {
+		t.Errorf("index hasil = %v, ingin 0, 1 dan 2 masing-masing sekali", seen)
+	}
+	summary := lines[3]
+	if summary["type"] != "summary" || summary["total"] != float64(3) || summary["succeeded"] != float64(2) || summary["failed"] != float64(1) {
+		t.Errorf("ringkasan = %v, ingin total 3, succeeded 2, failed 1", summary)
+	}
+}
@@ -0,0 +1,20 @@
+// File ini berisi controller untuk daftar dataset yang bisa dicari.
+
+package controllers
+
+import (
+	"main/services" // Import katalog dataset
+	"main/utils"    // Import envelope response standar
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// ListDatasets membuat handler yang mengembalikan nama semua dataset dan dataset default
+func ListDatasets(catalog *services.Catalog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		utils.Success(c, gin.H{
+			"datasets": catalog.Names(),   // Nama dataset (la2, la1, ...)
+			"default":  catalog.Default(), // Dataset yang dipakai jika request tidak menyebut dataset
+		})
+	}
+}
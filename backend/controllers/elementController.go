@@ -0,0 +1,42 @@
+// File ini berisi controller untuk autocomplete nama elemen.
+
+package controllers
+
+import (
+	"main/services" // Import katalog dataset
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+	"strconv"       // Untuk membaca parameter limit
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// defaultSuggestLimit adalah jumlah saran jika query tidak menyebut limit
+const defaultSuggestLimit = 10
+
+// SuggestElements membuat handler autocomplete: nama elemen yang diawali atau mirip
+// dengan q, diurutkan dari awalan lalu jarak edit terkecil
+func SuggestElements(catalog *services.Catalog) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		searcher, ok := catalog.Searcher(c.Query("dataset")) // Dataset -- kosong berarti dataset default
+		if !ok {
+			utils.Error(c, http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset")
+			return
+		}
+		limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(defaultSuggestLimit)))
+		if err != nil || limit <= 0 {
+			utils.ValidationFailed(c, []utils.FieldError{{Field: "limit", Message: "must be a positive integer"}})
+			return
+		}
+
+		suggestions, err := searcher.Suggest(c.Query("q"), limit)
+		if err != nil {
+			utils.InternalError(c, "Failed to read elements", err)
+			return
+		}
+		utils.Success(c, gin.H{
+			"query":       c.Query("q"),
+			"suggestions": suggestions, // Nama elemen beserta ikon dan jarak edit
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"main/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fixtureRows adalah graf resep kecil untuk test controller:
+//
+//	Mud   = Water + Earth
+//	Lava  = Earth + Fire
+//	Stone = Lava + Air
+//	Brick = Mud + Fire | Stone + Fire
+//	Ghost = Ghost + Ghost (tidak bisa dibuat dari elemen dasar)
+var fixtureRows = []services.ElementRow{
+	{Element: "Air"},
+	{Element: "Earth"},
+	{Element: "Fire"},
+	{Element: "Water"},
+	{Element: "Mud", Item1: "Water", Item2: "Earth"},
+	{Element: "Lava", Item1: "Earth", Item2: "Fire"},
+	{Element: "Stone", Item1: "Lava", Item2: "Air"},
+	{Element: "Brick", Item1: "Mud", Item2: "Fire"},
+	{Element: "Brick", Item1: "Stone", Item2: "Fire"},
+	{Element: "Ghost", Item1: "Ghost", Item2: "Ghost"},
+}
+
+// newFixtureCatalog membuat katalog dengan satu dataset default berisi fixtureRows
+func newFixtureCatalog() *services.Catalog {
+	catalog := services.NewCatalog()
+	catalog.Add(services.DefaultDataset, services.NewMemoryRepository(fixtureRows, nil))
+	return catalog
+}
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// postJSON mengirim body sebagai JSON ke handler dan mengembalikan respons
+func postJSON(t *testing.T, handler http.Handler, path string, body any) *httptest.ResponseRecorder {
+	t.Helper()
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatalf("encode body: %v", err)
+	}
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(data))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+// decodeJSON mendekode body respons ke map
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder) map[string]any {
+	t.Helper()
+	var body map[string]any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("respons bukan JSON yang valid: %v\n%s", err, rec.Body.String())
+	}
+	return body
+}
+
+// readNDJSON mendekode setiap baris body NDJSON sebagai satu objek JSON
+func readNDJSON(t *testing.T, rec *httptest.ResponseRecorder) []map[string]any {
+	t.Helper()
+	var lines []map[string]any
+	scanner := bufio.NewScanner(bytes.NewReader(rec.Body.Bytes()))
+	for scanner.Scan() {
+		var line map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+			t.Fatalf("baris %q bukan JSON yang valid: %v", scanner.Text(), err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
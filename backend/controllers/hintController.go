@@ -0,0 +1,77 @@
+// File ini berisi controller untuk saran kombinasi berikutnya (hint) bagi pemain.
+
+package controllers
+
+import (
+	"errors"        // Untuk membedakan jenis error dari service
+	"main/services" // Import hint engine dan katalog dataset
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// defaultHintLimit adalah jumlah saran jika request tidak menyebut limit
+const defaultHintLimit = 5
+
+// SuggestHints membuat handler yang menyarankan kombinasi berikutnya dari elemen
+// yang sudah ditemukan pemain. players boleh nil jika profil pemain tidak tersedia.
+func SuggestHints(catalog *services.Catalog, players *services.PlayerStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody struct {
+			Discovered []string `json:"discovered"` // Elemen yang sudah ditemukan -- kosong berarti elemen dasar saja
+			Goal       string   `json:"goal"`       // Elemen tujuan (opsional)
+			Limit      int      `json:"limit"`      // Jumlah saran maksimal
+			Dataset    string   `json:"dataset"`    // Nama dataset -- kosong berarti dataset default
+			Player     string   `json:"player"`     // ID profil pemain -- dipakai jika discovered kosong
+		}
+		if !utils.BindJSON(c, &requestBody, utils.Strict(c)) {
+			return // Error 400 sudah dikirim beserta field yang salah
+		}
+		if requestBody.Limit <= 0 {
+			requestBody.Limit = defaultHintLimit
+		}
+
+		if requestBody.Player != "" && len(requestBody.Discovered) == 0 {
+			if players == nil {
+				utils.Error(c, http.StatusServiceUnavailable, utils.CodePlayersDisabled, "Player profiles are disabled")
+				return
+			}
+			profile, err := players.Profile(requestBody.Player)
+			if errors.Is(err, services.ErrPlayerNotFound) {
+				utils.Error(c, http.StatusNotFound, utils.CodePlayerNotFound, "Unknown player")
+				return
+			}
+			if err != nil {
+				utils.InternalError(c, "Failed to load player", err)
+				return
+			}
+			if requestBody.Dataset != "" && requestBody.Dataset != profile.Dataset {
+				utils.Error(c, http.StatusBadRequest, utils.CodeDatasetConflict, "Player belongs to another dataset") // Penemuan profil tidak berlaku di dataset lain
+				return
+			}
+			requestBody.Discovered = profile.Elements()
+			requestBody.Dataset = profile.Dataset
+		}
+
+		repo, ok := catalog.Repository(requestBody.Dataset)
+		if !ok {
+			utils.Error(c, http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset") // Dataset tidak ada di katalog
+			return
+		}
+
+		hints, err := services.Hints(repo, requestBody.Discovered, requestBody.Goal, requestBody.Limit)
+		if errors.Is(err, services.ErrElementNotFound) {
+			utils.Error(c, http.StatusBadRequest, utils.CodeElementNotFound, err.Error()) // Elemen di discovered atau goal tidak ada
+			return
+		}
+		if err != nil {
+			utils.InternalError(c, "Failed to compute hints", err)
+			return
+		}
+
+		utils.Success(c, gin.H{
+			"hints": hints, // Kombinasi yang disarankan, terbaik lebih dulu, beserta alasannya
+		})
+	}
+}
@@ -0,0 +1,57 @@
+// File ini berisi controller untuk rute membuka semua elemen (speedrun).
+
+package controllers
+
+import (
+	"main/services" // Import planner dan katalog dataset
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+	"sync"          // Untuk cache rute per dataset
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// FullGamePlan membuat handler yang mengembalikan urutan kombinasi untuk membuka
+// semua elemen dataset. Dataset tidak berubah selama server berjalan, jadi rute
+// dihitung sekali per dataset lalu disimpan.
+func FullGamePlan(catalog *services.Catalog) gin.HandlerFunc {
+	var mu sync.Mutex
+	plans := make(map[string]*services.FullPlan)
+
+	return func(c *gin.Context) {
+		name := c.Query("dataset") // Nama dataset -- kosong berarti dataset default
+		if name == "" {
+			name = catalog.Default()
+		}
+		repo, ok := catalog.Repository(name)
+		if !ok {
+			utils.Error(c, http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset") // Dataset tidak ada di katalog
+			return
+		}
+
+		mu.Lock()
+		defer mu.Unlock()
+		plan, ok := plans[name]
+		if !ok {
+			memory, err := services.LoadMemoryRepository(repo) // Planner bekerja di data memori
+			if err == nil {
+				plan, err = services.PlanFullGame(memory)
+			}
+			if err != nil {
+				utils.InternalError(c, "Failed to compute plan", err)
+				return
+			}
+			plans[name] = plan
+		}
+
+		utils.Success(c, gin.H{
+			"dataset":     name,
+			"totalSteps":  len(plan.Steps),  // Jumlah kombinasi di rute
+			"basic":       plan.Basic,       // Elemen dasar di awal permainan
+			"discovered":  plan.Discovered,  // Elemen yang terbuka di akhir rute
+			"unreachable": plan.Unreachable, // Elemen yang tidak bisa dibuat dari elemen dasar
+			"checkpoints": plan.Checkpoints, // Ringkasan per tier
+			"steps":       plan.Steps,       // Urutan kombinasi
+		})
+	}
+}
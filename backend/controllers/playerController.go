@@ -0,0 +1,153 @@
+// File ini berisi controller profil pemain: impor progres, catat penemuan baru,
+// dan persentase penyelesaian per tier.
+
+package controllers
+
+import (
+	"errors"        // Untuk membedakan jenis error dari service
+	"main/services" // Import penyimpanan profil pemain dan katalog dataset
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// GetPlayer membuat handler yang mengembalikan profil pemain beserta semua penemuannya
+func GetPlayer(players *services.PlayerStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profile, ok := loadProfile(c, players)
+		if !ok {
+			return
+		}
+		utils.Success(c, profile)
+	}
+}
+
+// ImportPlayer membuat handler yang menambahkan daftar elemen yang sudah ditemukan
+// ke profil pemain, misalnya dari save game lama. Profil dibuat jika belum ada.
+func ImportPlayer(catalog *services.Catalog, players *services.PlayerStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody struct {
+			Discovered []string `json:"discovered" binding:"required"` // Elemen yang sudah ditemukan
+			Dataset    string   `json:"dataset"`                       // Dataset profil baru -- kosong berarti dataset default
+		}
+		if !utils.BindJSON(c, &requestBody, utils.Strict(c)) {
+			return // Error 400 sudah dikirim beserta field yang salah
+		}
+
+		id := c.Param("id")
+		dataset := requestBody.Dataset
+		if dataset == "" {
+			dataset = catalog.Default()
+		}
+		profile, err := players.Profile(id)
+		switch {
+		case err == nil && requestBody.Dataset != "" && requestBody.Dataset != profile.Dataset:
+			utils.Error(c, http.StatusConflict, utils.CodeDatasetConflict, "Player belongs to another dataset") // Satu profil hanya untuk satu dataset
+			return
+		case err == nil:
+			dataset = profile.Dataset
+		case !errors.Is(err, services.ErrPlayerNotFound):
+			utils.InternalError(c, "Failed to load player", err)
+			return
+		}
+		repo, ok := catalog.Repository(dataset)
+		if !ok {
+			utils.Error(c, http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset")
+			return
+		}
+
+		added, err := players.Import(repo, id, dataset, requestBody.Discovered)
+		if errors.Is(err, services.ErrElementNotFound) {
+			utils.Error(c, http.StatusBadRequest, utils.CodeElementNotFound, err.Error())
+			return
+		}
+		if err != nil {
+			utils.InternalError(c, "Failed to save player", err)
+			return
+		}
+		utils.Success(c, gin.H{
+			"added": added, // Elemen yang baru tercatat di profil
+		})
+	}
+}
+
+// DiscoverElement membuat handler yang menggabungkan dua elemen milik pemain dan
+// mencatat elemen baru yang dihasilkan beserta kombinasinya
+func DiscoverElement(catalog *services.Catalog, players *services.PlayerStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var requestBody struct {
+			Item1 string `json:"item1" binding:"required"` // Elemen pertama yang digabungkan
+			Item2 string `json:"item2" binding:"required"` // Elemen kedua yang digabungkan
+		}
+		if !utils.BindJSON(c, &requestBody, utils.Strict(c)) {
+			return // Error 400 sudah dikirim beserta field yang salah
+		}
+
+		profile, ok := loadProfile(c, players)
+		if !ok {
+			return
+		}
+		repo, ok := catalog.Repository(profile.Dataset)
+		if !ok {
+			utils.Error(c, http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset")
+			return
+		}
+
+		results, added, err := players.Craft(repo, profile.ID, requestBody.Item1, requestBody.Item2)
+		if errors.Is(err, services.ErrNotDiscovered) {
+			utils.Error(c, http.StatusBadRequest, utils.CodeElementNotDiscovered, err.Error()) // Pemain belum punya salah satu elemen
+			return
+		}
+		if err != nil {
+			utils.InternalError(c, "Failed to save player", err)
+			return
+		}
+		utils.Success(c, gin.H{
+			"results": results, // Semua hasil kombinasi (kosong jika tidak menghasilkan apa-apa)
+			"added":   added,   // Hasil yang baru pertama kali ditemukan
+		})
+	}
+}
+
+// PlayerProgress membuat handler yang mengembalikan persentase penyelesaian per tier
+func PlayerProgress(catalog *services.Catalog, players *services.PlayerStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		profile, ok := loadProfile(c, players)
+		if !ok {
+			return
+		}
+		repo, ok := catalog.Repository(profile.Dataset)
+		if !ok {
+			utils.Error(c, http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset")
+			return
+		}
+
+		tiers, overall, err := services.Progress(repo, profile)
+		if err != nil {
+			utils.InternalError(c, "Failed to compute progress", err)
+			return
+		}
+		utils.Success(c, gin.H{
+			"dataset":    profile.Dataset,
+			"total":      overall.Total,      // Elemen yang bisa dibuat dari elemen dasar
+			"discovered": overall.Discovered, // Elemen yang sudah ditemukan pemain
+			"percent":    overall.Percent,    // Persentase penyelesaian keseluruhan
+			"tiers":      tiers,              // Persentase penyelesaian per tier
+		})
+	}
+}
+
+// loadProfile membaca profil dari parameter :id dan menulis respons error jika gagal
+func loadProfile(c *gin.Context, players *services.PlayerStore) (*services.Profile, bool) {
+	profile, err := players.Profile(c.Param("id"))
+	if errors.Is(err, services.ErrPlayerNotFound) {
+		utils.Error(c, http.StatusNotFound, utils.CodePlayerNotFound, "Unknown player")
+		return nil, false
+	}
+	if err != nil {
+		utils.InternalError(c, "Failed to load player", err)
+		return nil, false
+	}
+	return profile, true
+}
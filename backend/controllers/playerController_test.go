@@ -0,0 +1,132 @@
+//go:build cgo
+
+package controllers
+
+import (
+	"database/sql"
+	"net/http"
+	"path/filepath"
+	"testing"
+
+	"main/services"
+	"main/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newFixturePlayers membuka penyimpanan profil pemain di direktori sementara
+func newFixturePlayers(t *testing.T) *services.PlayerStore {
+	t.Helper()
+	players, err := services.OpenPlayerStore(filepath.Join(t.TempDir(), "players.db"))
+	if err != nil {
+		t.Fatalf("OpenPlayerStore error: %v", err)
+	}
+	return players
+}
+
+// newPlayerRouter membuat router dengan endpoint profil pemain
+func newPlayerRouter(catalog *services.Catalog, players *services.PlayerStore) *gin.Engine {
+	router := gin.New()
+	router.POST("/api/players/:id/import", ImportPlayer(catalog, players))
+	router.POST("/api/players/:id/discoveries", DiscoverElement(catalog, players))
+	return router
+}
+
+func TestPlayerValidation(t *testing.T) {
+	router := newPlayerRouter(newFixtureCatalog(), newFixturePlayers(t))
+	if rec := postJSON(t, router, "/api/players/andi/import", map[string]any{"discovered": []string{"Mud"}}); rec.Code != http.StatusOK {
+		t.Fatalf("impor awal: status HTTP = %d\n%s", rec.Code, rec.Body.String())
+	}
+
+	tests := []struct {
+		name  string
+		path  string
+		body  map[string]any
+		field string
+	}{
+		{name: "impor tanpa discovered", path: "/api/players/andi/import", body: map[string]any{"dataset": services.DefaultDataset}, field: "discovered"},
+		{name: "gabung tanpa item1", path: "/api/players/andi/discoveries", body: map[string]any{"item2": "Fire"}, field: "item1"},
+		{name: "gabung dengan item2 kosong", path: "/api/players/andi/discoveries", body: map[string]any{"item1": "Mud", "item2": ""}, field: "item2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postJSON(t, router, tt.path, tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status HTTP = %d, ingin %d\n%s", rec.Code, http.StatusBadRequest, rec.Body.String())
+			}
+			body := decodeJSON(t, rec)
+			if body["code"] != utils.CodeValidationFailed {
+				t.Errorf("code = %v, ingin %s", body["code"], utils.CodeValidationFailed)
+			}
+			errs, _ := body["errors"].([]any)
+			if len(errs) != 1 {
+				t.Fatalf("errors = %v, ingin satu field", body["errors"])
+			}
+			if first, _ := errs[0].(map[string]any); first["field"] != tt.field {
+				t.Errorf("field = %v, ingin %s", first["field"], tt.field)
+			}
+		})
+	}
+}
+
+func TestPlayerStrictBinding(t *testing.T) {
+	router := newPlayerRouter(newFixtureCatalog(), newFixturePlayers(t))
+	body := map[string]any{"discovered": []string{"Mud"}, "inventory": []string{"Lava"}}
+	if rec := postJSON(t, router, "/api/players/andi/import", body); rec.Code != http.StatusOK {
+		t.Errorf("tanpa strict: status HTTP = %d, ingin %d", rec.Code, http.StatusOK)
+	}
+	if rec := postJSON(t, router, "/api/players/andi/import?"+utils.StrictParam+"=true", body); rec.Code != http.StatusBadRequest {
+		t.Errorf("strict: status HTTP = %d, ingin %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestSearchRecipePlayer(t *testing.T) {
+	catalog := newFixtureCatalog()
+	catalog.Add("la1", services.NewMemoryRepository(fixtureRows, nil))
+	repo, _ := catalog.Repository(services.DefaultDataset)
+	players := newFixturePlayers(t)
+	if _, err := players.Import(repo, "andi", services.DefaultDataset, []string{"Mud"}); err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+
+	// Penyimpanan profil yang database-nya sudah ditutup
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "players.db"))
+	if err != nil {
+		t.Fatalf("sql.Open error: %v", err)
+	}
+	broken, err := services.NewPlayerStore(db)
+	if err != nil {
+		t.Fatalf("NewPlayerStore error: %v", err)
+	}
+	db.Close()
+
+	tests := []struct {
+		name       string
+		players    *services.PlayerStore
+		player     string
+		dataset    string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "profil ada", players: players, player: "andi", wantStatus: http.StatusOK, wantCode: "OK"},
+		{name: "dataset profil", players: players, player: "andi", dataset: services.DefaultDataset, wantStatus: http.StatusOK, wantCode: "OK"},
+		{name: "dataset lain", players: players, player: "andi", dataset: "la1", wantStatus: http.StatusBadRequest, wantCode: utils.CodeDatasetConflict},
+		{name: "profil tidak ada", players: players, player: "budi", wantStatus: http.StatusNotFound, wantCode: utils.CodePlayerNotFound},
+		{name: "database profil rusak", players: broken, player: "andi", wantStatus: http.StatusInternalServerError, wantCode: utils.CodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/api/search", SearchRecipe(catalog, tt.players, nil))
+			rec := postJSON(t, router, "/api/search", map[string]any{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All", "player": tt.player, "dataset": tt.dataset})
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status HTTP = %d, ingin %d\n%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if code := decodeJSON(t, rec)["code"]; code != tt.wantCode {
+				t.Errorf("code = %v, ingin %s", code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -2,45 +2,459 @@
 package controllers
 
 import (
+	"context"       // Untuk menghentikan pencarian yang tidak ditunggu lagi
+	"errors"        // Untuk membedakan error pencarian
+	"fmt"           // Untuk menyusun pesan error per field
 	"main/services" // Import service pencarian resep
+	"main/utils"    // Import envelope response standar
 	"net/http"      // Untuk kebutuhan HTTP response
+	"net/url"       // Untuk menyusun query yang dinormalisasi
+	"slices"        // Untuk mengurutkan daftar elemen di ETag
+	"strconv"       // Untuk menulis maxRecipes di ETag
+	"strings"       // Untuk memecah kombinasi di exclude
 
 	"github.com/gin-gonic/gin" // Framework web Gin
 )
 
-func SearchRecipe(c *gin.Context) {
-  var requestBody struct {
-    ElementName string `json:"elementName"` // Nama elemen yang dicari
-    Algorithm   string `json:"algorithm"`   // Algoritma pencarian (BFS, DFS, Bidirectional)
-    RecipeType  string `json:"recipeType"`  // Tipe resep (misal: One Recipe)
-    MaxRecipes  int    `json:"maxRecipes"`  // Maksimal jumlah resep -- buat RecipeType = "Limit .. "
-    // TargetName  string `json:"targetName"`  // Target untuk buat Algoritma Bidirectional  -- ga kepake
+// SearchRecipe membuat handler pencarian resep pada dataset dari katalog.
+// players boleh nil jika profil pemain tidak tersedia.
+func SearchRecipe(catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    searchRecipe(c, catalog, players, traces)
   }
+}
 
-  if err := c.ShouldBindJSON(&requestBody); err != nil { // Bind dan validasi request body dari frontend
-    c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body"}) // Jika gagal, kirim error 400
+// searchRequest adalah body request pencarian. Aturan di tag binding diperiksa oleh
+// utils.BindJSON, dan setiap field yang salah dilaporkan dengan nama JSON-nya.
+// maxRecipes dibatasi 1000; untuk semua resep gunakan recipeType "All".
+type searchRequest struct {
+  ElementName string   `json:"elementName" binding:"required"`                                            // Nama elemen yang dicari
+  Algorithm   string   `json:"algorithm" binding:"required,oneof=BFS DFS Bidirectional"`                  // Algoritma pencarian (BFS, DFS, Bidirectional)
+  RecipeType  string   `json:"recipeType" binding:"required,oneof=One Limit All"`                         // Tipe resep: One, Limit atau All
+  MaxRecipes  int      `json:"maxRecipes" binding:"required_if=RecipeType Limit,omitempty,min=1,max=1000"` // Maksimal jumlah resep -- wajib untuk RecipeType = "Limit"
+  Dataset     string   `json:"dataset"`                                                                   // Nama dataset (la2, la1, ...) -- kosong berarti dataset default
+  Owned       []string `json:"owned" binding:"max=1000"`                                                  // Elemen yang sudah dimiliki pemain -- dianggap seperti elemen dasar
+  Exclude     []string `json:"exclude" binding:"max=1000"`                                                // Elemen atau kombinasi ("Clay + Life") yang tidak boleh dipakai
+  Require     []string `json:"require" binding:"max=1000"`                                                // Elemen yang wajib muncul di pohon resep
+  Player      string   `json:"player"`                                                                    // ID profil pemain -- elemen yang sudah ditemukan dipakai sebagai inventory
+  Trace       bool     `json:"trace"`                                                                     // Rekam setiap langkah traversal, lihat GET /api/traces/:id
+  // TargetName  string `json:"targetName"`  // Target untuk buat Algoritma Bidirectional  -- ga kepake
+}
+
+// searchQuery adalah parameter GET /api/search. Namanya lebih pendek dari field JSON
+// supaya URL mudah dibagikan; owned, exclude dan require boleh diulang atau dipisah koma.
+type searchQuery struct {
+  Element   string   `form:"element" binding:"required"`
+  Algorithm string   `form:"algorithm" binding:"required,oneof=BFS DFS Bidirectional"`
+  Type      string   `form:"type" binding:"required,oneof=One Limit All"`
+  Max       int      `form:"max" binding:"required_if=Type Limit,omitempty,min=1,max=1000"`
+  Dataset   string   `form:"dataset"`
+  Owned     []string `form:"owned" binding:"max=1000"`
+  Exclude   []string `form:"exclude" binding:"max=1000"` // "+" di URL berarti spasi, tulis kombinasi sebagai Clay%20%2B%20Life
+  Require   []string `form:"require" binding:"max=1000"`
+  Player    string   `form:"player"`
+  Trace     bool     `form:"trace"`
+}
+
+// request mengubah parameter query menjadi request pencarian yang sama dengan POST
+func (q searchQuery) request() searchRequest {
+  return searchRequest{
+    ElementName: q.Element,
+    Algorithm:   q.Algorithm,
+    RecipeType:  q.Type,
+    MaxRecipes:  q.Max,
+    Dataset:     q.Dataset,
+    Owned:       splitValues(q.Owned),
+    Exclude:     splitValues(q.Exclude),
+    Require:     splitValues(q.Require),
+    Player:      q.Player,
+    Trace:       q.Trace,
+  }
+}
+
+// splitValues memecah parameter yang dipisah koma, mengabaikan item kosong
+func splitValues(values []string) []string {
+  var items []string
+  for _, value := range values {
+    for _, item := range strings.Split(value, ",") {
+      if item = strings.TrimSpace(item); item != "" {
+        items = append(items, item)
+      }
+    }
+  }
+  return items
+}
+
+// searchCacheControl berlaku untuk GET /api/search tanpa player. Dataset tidak berubah
+// selama server berjalan, dan ETag memastikan cache diperbarui setelah dataset diganti.
+const searchCacheControl = "public, max-age=300"
+
+// SearchRecipeQuery membuat handler GET /api/search. Semantiknya sama dengan POST, tetapi
+// hasilnya bisa di-bookmark dan di-cache: response membawa ETag dan Cache-Control, dan
+// request dengan If-None-Match yang cocok dijawab 304, kecuali jika traversal direkam.
+func SearchRecipeQuery(catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore) gin.HandlerFunc {
+  return func(c *gin.Context) {
+    var query searchQuery
+    if !utils.BindQuery(c, &query, utils.Strict(c)) {
+      return // Error 400 sudah dikirim beserta parameter yang salah
+    }
+    runSearch(c, catalog, players, traces, query.request(), true)
+  }
+}
+
+func searchRecipe(c *gin.Context, catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore) {
+  var requestBody searchRequest
+  if !utils.BindJSON(c, &requestBody, utils.Strict(c)) { // Bind dan validasi request body dari frontend; ?strict=true menolak field yang tidak dikenal
+    return // Error 400 sudah dikirim beserta field yang salah
+  }
+  runSearch(c, catalog, players, traces, requestBody, false)
+}
+
+// runSearch menjalankan request pencarian yang sudah lolos validasi. cacheable memasang
+// ETag dan Cache-Control, dan menjawab 304 sebelum pencarian dijalankan jika ETag cocok.
+func runSearch(c *gin.Context, catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore, requestBody searchRequest, cacheable bool) {
+  prepared, failure := prepareSearch(catalog, players, traces, requestBody)
+  if failure != nil {
+    failure.send(c)
     return
   }
+  if cacheable && prepared.trace != nil {
+    c.Header("Cache-Control", "no-store") // Setiap request merekam trace baru
+    cacheable = false
+  }
 
-  var results []interface{}    // Untuk menampung hasil pencarian -- menyimpan array (tree) resep ketika ditemukan
-  var nodesVisited int         // Untuk menghitung node yang dikunjungi 
-  var executionTime float64    // Untuk mencatat waktu eksekusi
+  if utils.WantsNDJSON(c) {
+    if cacheable {
+      c.Header("Vary", "Accept")
+      c.Header("Cache-Control", "no-store") // Hasil stream tidak di-cache
+    }
+    prepared.stream(c)
+    return
+  }
+
+  if cacheable {
+    c.Header("Vary", "Accept") // URL yang sama bisa dijawab JSON atau NDJSON
+    etag, err := searchETag(catalog, prepared)
+    if err != nil {
+      utils.InternalError(c, "Failed to read dataset", err)
+      return
+    }
+    cacheControl := searchCacheControl
+    if prepared.profile != nil {
+      cacheControl = "private, no-cache" // Profil bisa bertambah kapan saja, selalu cek ulang
+    }
+    if utils.NotModified(c, etag, cacheControl) {
+      return // Client sudah punya hasil yang sama
+    }
+  }
+
+  outcome := prepared.run(c.Request.Context())
+  if outcome == nil {
+    return // Client sudah putus, tidak ada yang perlu dikirim
+  }
+  outcome.send(c)
+}
 
-  switch requestBody.Algorithm { // Pilih algoritma pencarian sesuai permintaan frontend
-  case "BFS":
-    results, nodesVisited, executionTime = services.BFS(requestBody.ElementName, requestBody.RecipeType, requestBody.MaxRecipes) // Panggil BFS
-  case "DFS":
-    results, nodesVisited, executionTime = services.DFS(requestBody.ElementName, requestBody.RecipeType, requestBody.MaxRecipes) // Panggil DFS
-  case "Bidirectional":
-    results, nodesVisited, executionTime = services.Bidirectional(requestBody.ElementName, requestBody.RecipeType, requestBody.MaxRecipes) // Panggil Bidirectional
-  default:
-    c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid algorithm"}) // Jika algoritma tidak valid, kirim error 400
+// searchOutcome adalah hasil satu request pencarian sebelum ditulis sebagai envelope,
+// supaya pencarian tunggal dan batch memakai alur yang sama
+type searchOutcome struct {
+  httpStatus int
+  code       string
+  message    string
+  errs       []utils.FieldError
+  data       gin.H
+  err        error // Error internal, dicatat tanpa dikirim ke client
+}
+
+// failedSearch membuat outcome untuk request yang ditolak sebelum pencarian dijalankan
+func failedSearch(httpStatus int, code string, message string, errs ...utils.FieldError) *searchOutcome {
+  return &searchOutcome{httpStatus: httpStatus, code: code, message: message, errs: errs}
+}
+
+// send menulis outcome sebagai envelope
+func (o *searchOutcome) send(c *gin.Context) {
+  if o.err != nil {
+    utils.InternalError(c, o.message, o.err)
     return
   }
+  utils.Send(c, o.httpStatus, o.code, o.message, o.data, o.errs...)
+}
+
+// status mengikuti aturan field status envelope: error untuk HTTP 400 ke atas
+func (o *searchOutcome) status() string {
+  if o.httpStatus >= http.StatusBadRequest {
+    return utils.StatusError
+  }
+  return utils.StatusSuccess
+}
+
+// preparedSearch adalah request pencarian yang profil, dataset dan nama elemennya
+// sudah diperiksa, tinggal dijalankan
+type preparedSearch struct {
+  request  searchRequest
+  searcher *services.Searcher
+  profile  *services.Profile
+  traces   *services.TraceStore
+  trace    *services.Trace // Hanya jika request.Trace
+}
+
+// prepareSearch memuat profil pemain, memilih dataset dan memeriksa nama elemen di
+// opsi pencarian. Jika request ditolak, outcome error dikembalikan.
+func prepareSearch(catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore, requestBody searchRequest) (*preparedSearch, *searchOutcome) {
+  var profile *services.Profile
+  if requestBody.Player != "" {
+    if players == nil {
+      return nil, failedSearch(http.StatusServiceUnavailable, utils.CodePlayersDisabled, "Player profiles are disabled") // players.db tidak bisa dibuka
+    }
+    var err error
+    profile, err = players.Profile(requestBody.Player)
+    if errors.Is(err, services.ErrPlayerNotFound) {
+      return nil, failedSearch(http.StatusNotFound, utils.CodePlayerNotFound, "Unknown player")
+    }
+    if err != nil {
+      return nil, &searchOutcome{httpStatus: http.StatusInternalServerError, code: utils.CodeInternal, message: "Failed to load player", err: err} // players.db tidak bisa dibaca
+    }
+    if requestBody.Dataset != "" && requestBody.Dataset != profile.Dataset {
+      return nil, failedSearch(http.StatusBadRequest, utils.CodeDatasetConflict, "Player belongs to another dataset") // Inventory profil tidak berlaku di dataset lain
+    }
+    requestBody.Dataset = profile.Dataset // Cari di dataset milik profil
+  }
+
+  searcher, ok := catalog.Searcher(requestBody.Dataset) // Pilih dataset yang dicari
+  if !ok {
+    return nil, failedSearch(http.StatusBadRequest, utils.CodeUnknownDataset, "Unknown dataset") // Dataset tidak ada di katalog
+  }
+
+  // Nama elemen di owned, exclude dan require harus ada di dataset yang dicari
+  if errs := resolveOptionElements(searcher, &requestBody); len(errs) > 0 {
+    return nil, failedSearch(http.StatusUnprocessableEntity, utils.CodeUnknownElements, "Unknown elements in search options", errs...)
+  }
+  prepared := &preparedSearch{request: requestBody, searcher: searcher, profile: profile, traces: traces}
+  if requestBody.Trace {
+    prepared.trace = services.NewTrace(services.DefaultTraceLimit)
+    prepared.trace.Dataset = requestBody.Dataset
+    if prepared.trace.Dataset == "" {
+      prepared.trace.Dataset = catalog.Default()
+    }
+  }
+  return prepared, nil
+}
+
+// run menjalankan pencarian dan menyusun data response. Pencarian berhenti begitu ctx
+// selesai (client putus atau budget batch habis), dan hasilnya nil.
+func (p *preparedSearch) run(ctx context.Context) *searchOutcome {
+  request := p.request
+  options := p.options()
+  options.OnStep = func(services.Step) error {
+    return ctx.Err() // Diperiksa di setiap langkah traversal
+  }
+
+  // Jalankan algoritma pencarian sesuai permintaan frontend (BFS, DFS, Bidirectional)
+  result, err := p.searcher.Search(request.Algorithm, request.ElementName, request.RecipeType, request.MaxRecipes, options)
+  if err != nil && ctx.Err() != nil {
+    return nil
+  }
+  return p.outcome(result, err)
+}
+
+// options menyusun opsi pencarian dari request
+func (p *preparedSearch) options() services.SearchOptions {
+  return services.SearchOptions{
+    Owned:   p.request.Owned,   // Resep berhenti di elemen yang sudah dimiliki, diurutkan dari kombinasi baru paling sedikit
+    Exclude: p.request.Exclude, // Dipangkas saat traversal, bukan disaring setelahnya
+    Require: p.request.Require,
+    Profile: p.profile,
+    Trace:   p.trace, // nil jika traversal tidak direkam
+  }
+}
+
+// saveTrace menyimpan trace pencarian yang sudah selesai dan mengembalikan ID-nya,
+// atau string kosong jika traversal tidak direkam
+func (p *preparedSearch) saveTrace() string {
+  if p.trace == nil {
+    return ""
+  }
+  return p.traces.Add(p.trace)
+}
+
+// outcome menyusun data response dari hasil pencarian
+func (p *preparedSearch) outcome(result *services.SearchResult, err error) *searchOutcome {
+  if errors.Is(err, services.ErrUnknownAlgorithm) {
+    return failedSearch(http.StatusBadRequest, utils.CodeValidationFailed, "Request validation failed",
+      utils.FieldError{Field: "algorithm", Message: "must be one of " + strings.Join(services.Algorithms, ", ")})
+  }
+  if err != nil {
+    // Error lain bukan kesalahan client -- catat dan jawab dengan 500
+    return &searchOutcome{httpStatus: http.StatusInternalServerError, code: utils.CodeInternal, message: "Search failed", err: err}
+  }
+
+  data := gin.H{ // Kirim hasil pencarian ke frontend di dalam envelope
+    "results":       result.Results,       // Hasil pencarian (array pohon resep)
+    "nodesVisited":  result.NodesVisited,  // Jumlah node yang dikunjungi
+    "executionTime": result.ExecutionTime, // Lama waktu eksekusi (ms)
+  }
+  if result.Status == services.StatusElementNotFound {
+    // Elemen tidak ada -- sarankan nama yang mirip
+    suggestions, _ := p.searcher.Suggest(p.request.ElementName, 5)
+    data["element"] = p.request.ElementName
+    data["didYouMean"] = suggestions
+  }
+  if traceID := p.saveTrace(); traceID != "" {
+    data["traceId"] = traceID // Rekaman traversal, bisa diambil di GET /api/traces/:id
+  }
+  return &searchOutcome{
+    httpStatus: searchHTTPStatus[result.Status],
+    code:       string(result.Status),
+    message:    result.Message,
+    data:       data,
+  }
+}
+
+// recipeLine adalah satu baris NDJSON berisi pohon resep, dengan field pohon di level atas
+type recipeLine struct {
+  Type  string `json:"type"`  // "recipe"
+  Index int    `json:"index"` // Urutan resep ditemukan, mulai dari 0
+  *services.RecipeTree
+}
 
-  c.JSON(http.StatusOK, gin.H{ // Kirim hasil pencarian ke frontend dalam format JSON
-    "results":       results,        // Hasil pencarian (array pohon resep)
-    "nodesVisited":  nodesVisited,   // Jumlah node yang dikunjungi
-    "executionTime": executionTime,  // Lama waktu eksekusi (ms)
+// searchSummary adalah baris terakhir NDJSON pencarian
+type searchSummary struct {
+  Type          string  `json:"type"` // "summary"
+  Status        string  `json:"status"`
+  Code          string  `json:"code"`
+  Message       string  `json:"message"`
+  Recipes       int     `json:"recipes"` // Jumlah baris resep yang dikirim
+  NodesVisited  int     `json:"nodesVisited"`
+  ExecutionTime float64 `json:"executionTime"` // Lama waktu eksekusi (ms)
+  TraceID       string  `json:"traceId,omitempty"`
+}
+
+// stream menjalankan pencarian dan menulis setiap pohon resep sebagai satu baris NDJSON
+// begitu ditemukan, lalu satu baris ringkasan. Penulisan langsung ke writer Gin, jadi
+// pencarian ikut menunggu jika client lambat membaca dan berhenti jika client putus.
+// Header NDJSON baru dikirim saat resep pertama ditemukan, sehingga pencarian tanpa
+// resep tetap dijawab dengan envelope dan status HTTP yang sama seperti biasa.
+func (p *preparedSearch) stream(c *gin.Context) {
+  request := p.request
+  var write func(line interface{}) error
+  count := 0
+  result, err := p.searcher.SearchStream(request.Algorithm, request.ElementName, request.RecipeType, request.MaxRecipes, p.options(), func(tree *services.RecipeTree) error {
+    if err := c.Request.Context().Err(); err != nil {
+      return err // Client sudah putus, hentikan pencarian
+    }
+    if write == nil {
+      write = utils.StartNDJSON(c)
+    }
+    count++
+    return write(recipeLine{Type: "recipe", Index: count - 1, RecipeTree: tree})
   })
-}
\ No newline at end of file
+
+  if err != nil && c.Request.Context().Err() != nil {
+    return // Client putus, tidak ada yang bisa dikirim lagi
+  }
+  if err != nil && write != nil {
+    c.Error(err) // Dicatat oleh logger gin; stream berhenti tanpa baris ringkasan
+    return
+  }
+  if write == nil {
+    p.outcome(result, err).send(c) // Tidak ada resep yang dikirim, jawab dengan envelope (500 untuk error dataset)
+    return
+  }
+  write(searchSummary{
+    Type:          "summary",
+    Status:        utils.StatusSuccess,
+    Code:          string(result.Status),
+    Message:       result.Message,
+    Recipes:       count,
+    NodesVisited:  result.NodesVisited,
+    ExecutionTime: result.ExecutionTime,
+    TraceID:       p.saveTrace(),
+  })
+}
+
+// resolveOptionElements mengganti nama elemen di owned, exclude dan require dengan
+// ejaan dataset, dan melaporkan nama yang tidak ada beserta saran nama yang mirip
+func resolveOptionElements(searcher *services.Searcher, request *searchRequest) []utils.FieldError {
+  var errs []utils.FieldError
+  resolve := func(field string, name string) string {
+    element, ok, err := searcher.Resolve(name)
+    if err != nil {
+      return name
+    }
+    if ok {
+      return element
+    }
+    message := fmt.Sprintf("unknown element %q", strings.TrimSpace(name))
+    if suggestions, _ := searcher.Suggest(name, 1); len(suggestions) > 0 {
+      message += fmt.Sprintf(", did you mean %q?", suggestions[0].Name)
+    }
+    errs = append(errs, utils.FieldError{Field: field, Message: message})
+    return name
+  }
+
+  for i, name := range request.Owned {
+    request.Owned[i] = resolve(fmt.Sprintf("owned[%d]", i), name)
+  }
+  for i, name := range request.Require {
+    request.Require[i] = resolve(fmt.Sprintf("require[%d]", i), name)
+  }
+  for i, entry := range request.Exclude {
+    field := fmt.Sprintf("exclude[%d]", i)
+    if item1, item2, ok := strings.Cut(entry, "+"); ok { // Kombinasi "Item1 + Item2", urutan bebas
+      item1, item2 = resolve(field, item1), resolve(field, item2)
+      if item2 < item1 {
+        item1, item2 = item2, item1
+      }
+      request.Exclude[i] = item1 + " + " + item2
+    } else {
+      request.Exclude[i] = resolve(field, entry)
+    }
+  }
+  return errs
+}
+
+// searchETag membuat ETag pencarian dari versi dataset dan request yang dinormalisasi:
+// nama elemen dengan ejaan dataset, daftar yang diurutkan tanpa duplikat, dan maxRecipes
+// hanya untuk Limit. Untuk player, jumlah penemuan ikut dihitung karena profil bertambah.
+func searchETag(catalog *services.Catalog, prepared *preparedSearch) (string, error) {
+  searcher, request, profile := prepared.searcher, prepared.request, prepared.profile
+  version, err := searcher.Version()
+  if err != nil {
+    return "", err
+  }
+  element := strings.TrimSpace(request.ElementName)
+  if resolved, ok, err := searcher.Resolve(element); err == nil && ok {
+    element = resolved
+  }
+  dataset := request.Dataset
+  if dataset == "" {
+    dataset = catalog.Default()
+  }
+
+  query := url.Values{
+    "dataset":   {dataset},
+    "element":   {element},
+    "algorithm": {request.Algorithm},
+    "type":      {request.RecipeType},
+    "owned":     slices.Compact(slices.Sorted(slices.Values(request.Owned))),
+    "exclude":   slices.Compact(slices.Sorted(slices.Values(request.Exclude))),
+    "require":   slices.Compact(slices.Sorted(slices.Values(request.Require))),
+  }
+  if request.RecipeType == "Limit" {
+    query.Set("max", strconv.Itoa(request.MaxRecipes))
+  }
+  if profile != nil {
+    query.Set("player", profile.ID+"@"+strconv.Itoa(len(profile.Discoveries)))
+  }
+  return utils.WeakETag(version, query.Encode()), nil
+}
+
+// searchHTTPStatus memetakan status pencarian ke status HTTP. Elemen dasar bukan
+// error: permintaannya valid, hanya saja tidak ada yang perlu dibuat.
+var searchHTTPStatus = map[services.SearchStatus]int{
+  services.StatusOK:              http.StatusOK,
+  services.StatusLimitReached:    http.StatusOK,
+  services.StatusBasicElement:    http.StatusOK,
+  services.StatusElementNotFound: http.StatusNotFound,
+  services.StatusNoRecipe:        http.StatusUnprocessableEntity,
+}
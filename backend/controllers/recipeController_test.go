@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main/services"
+	"main/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestSearchRecipeStatus(t *testing.T) {
+	router := gin.New()
+	router.POST("/api/search", SearchRecipe(newFixtureCatalog(), nil, nil))
+
+	tests := []struct {
+		name       string
+		body       map[string]any
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "ok", body: map[string]any{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"}, wantStatus: http.StatusOK, wantCode: "OK"},
+		{name: "limit tercapai", body: map[string]any{"elementName": "Brick", "algorithm": "DFS", "recipeType": "Limit", "maxRecipes": 1}, wantStatus: http.StatusOK, wantCode: "LIMIT_REACHED"},
+		{name: "elemen dasar", body: map[string]any{"elementName": "Fire", "algorithm": "BFS", "recipeType": "All"}, wantStatus: http.StatusOK, wantCode: "BASIC_ELEMENT"},
+		{name: "elemen tidak ada", body: map[string]any{"elementName": "Brik", "algorithm": "BFS", "recipeType": "All"}, wantStatus: http.StatusNotFound, wantCode: "ELEMENT_NOT_FOUND"},
+		{name: "tanpa resep", body: map[string]any{"elementName": "Ghost", "algorithm": "Bidirectional", "recipeType": "All"}, wantStatus: http.StatusUnprocessableEntity, wantCode: "NO_RECIPE"},
+		{name: "algoritma tidak dikenal", body: map[string]any{"elementName": "Brick", "algorithm": "A*", "recipeType": "All"}, wantStatus: http.StatusBadRequest, wantCode: "VALIDATION_FAILED"},
+		{name: "dataset tidak dikenal", body: map[string]any{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All", "dataset": "myths"}, wantStatus: http.StatusBadRequest, wantCode: "UNKNOWN_DATASET"},
+		{name: "profil pemain dimatikan", body: map[string]any{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All", "player": "andi"}, wantStatus: http.StatusServiceUnavailable, wantCode: "PLAYERS_DISABLED"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := postJSON(t, router, "/api/search", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status HTTP = %d, ingin %d\n%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if code := decodeJSON(t, rec)["code"]; code != tt.wantCode {
+				t.Errorf("code = %v, ingin %s", code, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestSearchRecipeDidYouMean(t *testing.T) {
+	router := gin.New()
+	router.POST("/api/search", SearchRecipe(newFixtureCatalog(), nil, nil))
+
+	rec := postJSON(t, router, "/api/search", map[string]any{"elementName": "Brik", "algorithm": "BFS", "recipeType": "One"})
+	data, _ := decodeJSON(t, rec)["data"].(map[string]any)
+	suggestions, _ := data["didYouMean"].([]any)
+	if len(suggestions) == 0 {
+		t.Fatalf("didYouMean kosong: %s", rec.Body.String())
+	}
+	if first, _ := suggestions[0].(map[string]any); first["name"] != "Brick" {
+		t.Errorf("saran pertama = %v, ingin Brick", suggestions[0])
+	}
+}
+
+// getSearch mengirim GET /api/search dengan query dan header If-None-Match opsional
+func getSearch(handler http.Handler, query string, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/api/search?"+query, nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestSearchRecipeQueryETag(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/search", SearchRecipeQuery(newFixtureCatalog(), nil, nil))
+
+	first := getSearch(router, "element=Brick&algorithm=BFS&type=All", "")
+	if first.Code != http.StatusOK {
+		t.Fatalf("status HTTP = %d, ingin 200\n%s", first.Code, first.Body.String())
+	}
+	etag := first.Header().Get("ETag")
+	if !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("ETag = %q, ingin ETag lemah", etag)
+	}
+	if cacheControl := first.Header().Get("Cache-Control"); cacheControl != searchCacheControl {
+		t.Errorf("Cache-Control = %q, ingin %q", cacheControl, searchCacheControl)
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "etag sama", query: "element=Brick&algorithm=BFS&type=All", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "etag kuat", query: "element=Brick&algorithm=BFS&type=All", ifNoneMatch: strings.TrimPrefix(etag, "W/"), wantStatus: http.StatusNotModified},
+		{name: "salah satu dari daftar", query: "element=Brick&algorithm=BFS&type=All", ifNoneMatch: `W/"lama", ` + etag, wantStatus: http.StatusNotModified},
+		{name: "wildcard", query: "element=Brick&algorithm=BFS&type=All", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "nama elemen dinormalisasi", query: "element=brick&algorithm=BFS&type=All", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "max diabaikan selain Limit", query: "element=Brick&algorithm=BFS&type=All&max=3", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "algoritma lain", query: "element=Brick&algorithm=DFS&type=All", ifNoneMatch: etag, wantStatus: http.StatusOK},
+		{name: "etag lain", query: "element=Brick&algorithm=BFS&type=All", ifNoneMatch: `W/"lama"`, wantStatus: http.StatusOK},
+		{name: "parameter wajib kosong", query: "algorithm=BFS&type=All", ifNoneMatch: etag, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getSearch(router, tt.query, tt.ifNoneMatch)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status HTTP = %d, ingin %d\n%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if rec.Code == http.StatusNotModified && rec.Body.Len() != 0 {
+				t.Errorf("response 304 tidak boleh punya body, dapat %q", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestSearchRecipeNDJSON(t *testing.T) {
+	router := gin.New()
+	router.POST("/api/search", SearchRecipe(newFixtureCatalog(), nil, nil))
+	router.GET("/api/search", SearchRecipeQuery(newFixtureCatalog(), nil, nil))
+
+	send := func(method, target string, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", utils.NDJSONContentType)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("resep per baris lalu ringkasan", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/search", `{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"}`)
+		if contentType := rec.Header().Get("Content-Type"); contentType != utils.NDJSONContentType {
+			t.Fatalf("Content-Type = %q, ingin %q\n%s", contentType, utils.NDJSONContentType, rec.Body.String())
+		}
+		lines := readNDJSON(t, rec)
+		if len(lines) != 3 {
+			t.Fatalf("jumlah baris = %d, ingin 2 resep dan 1 ringkasan\n%s", len(lines), rec.Body.String())
+		}
+		for i, line := range lines[:2] {
+			if line["type"] != "recipe" || line["index"] != float64(i) || line["name"] != "Brick" {
+				t.Errorf("baris %d = %v, ingin resep Brick dengan index %d", i, line, i)
+			}
+			if recipe, _ := line["recipe"].([]any); len(recipe) == 0 {
+				t.Errorf("baris %d tidak membawa langkah resep", i)
+			}
+		}
+		summary := lines[2]
+		if summary["type"] != "summary" || summary["status"] != utils.StatusSuccess || summary["code"] != "OK" || summary["recipes"] != float64(2) {
+			t.Errorf("ringkasan = %v, ingin success/OK dengan 2 resep", summary)
+		}
+	})
+
+	t.Run("tanpa resep tetap envelope", func(t *testing.T) {
+		rec := send(http.MethodPost, "/api/search", `{"elementName": "Ghost", "algorithm": "DFS", "recipeType": "All"}`)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status HTTP = %d, ingin 422\n%s", rec.Code, rec.Body.String())
+		}
+		if code := decodeJSON(t, rec)["code"]; code != "NO_RECIPE" {
+			t.Errorf("code = %v, ingin NO_RECIPE", code)
+		}
+	})
+
+	t.Run("GET tidak di-cache", func(t *testing.T) {
+		rec := send(http.MethodGet, "/api/search?element=Brick&algorithm=DFS&type=One", "")
+		if cacheControl := rec.Header().Get("Cache-Control"); cacheControl != "no-store" {
+			t.Errorf("Cache-Control = %q, ingin no-store", cacheControl)
+		}
+		if vary := rec.Header().Get("Vary"); vary != "Accept" {
+			t.Errorf("Vary = %q, ingin Accept", vary)
+		}
+		if lines := readNDJSON(t, rec); len(lines) != 2 || lines[1]["code"] != "LIMIT_REACHED" {
+			t.Errorf("baris = %v, ingin 1 resep dan ringkasan LIMIT_REACHED", lines)
+		}
+	})
+}
+
+// failingRepository gagal di setiap pencarian resep
+type failingRepository struct {
+	services.RecipeRepository
+}
+
+func (failingRepository) Recipes(string) ([]services.Combination, error) {
+	return nil, errors.New("dataset tidak bisa dibaca")
+}
+
+func TestSearchRecipeRepositoryError(t *testing.T) {
+	catalog := services.NewCatalog()
+	catalog.Add(services.DefaultDataset, failingRepository{services.NewMemoryRepository(fixtureRows, nil)})
+	router := gin.New()
+	router.POST("/api/search", SearchRecipe(catalog, nil, nil))
+
+	rec := postJSON(t, router, "/api/search", map[string]any{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"})
+	if rec.Code != http.StatusInternalServerError {
+		t.Fatalf("status HTTP = %d, ingin %d\n%s", rec.Code, http.StatusInternalServerError, rec.Body.String())
+	}
+	if code := decodeJSON(t, rec)["code"]; code != utils.CodeInternal {
+		t.Errorf("code = %v, ingin %s", code, utils.CodeInternal)
+	}
+}
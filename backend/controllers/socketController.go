@@ -0,0 +1,397 @@
+// File ini berisi controller WebSocket untuk melihat jalannya pencarian secara langsung.
+// Setiap langkah traversal (node yang masuk queue, dikunjungi atau dilewati karena siklus,
+// kombinasi yang dibuka, dan resep yang ditemukan atau dibuang karena duplikat) dikirim
+// sebagai event, dengan kecepatan yang bisa diatur, dijeda, dan dijalankan langkah demi langkah.
+
+package controllers
+
+import (
+	"bytes"         // Untuk membaca request pencarian di pesan start
+	"context"       // Untuk menghentikan pencarian
+	"encoding/json" // Untuk membaca pesan client
+	"main/services" // Import katalog dataset dan penyimpanan profil
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+	"sync"          // Untuk menjaga state sesi
+	"time"          // Untuk mengatur kecepatan langkah
+
+	"github.com/gin-gonic/gin"     // Framework web Gin
+	"github.com/gorilla/websocket" // Protokol WebSocket
+)
+
+// Nilai default dan batas WebSocket pencarian
+const (
+	defaultStepsPerSecond = 10
+	socketPingInterval    = 30 * time.Second // Lebih pendek dari proxy_read_timeout nginx (60 detik) saat pencarian dijeda
+	socketWriteTimeout    = 10 * time.Second
+	socketReadLimit       = 64 << 10 // Ukuran maksimal pesan client
+)
+
+// socketUpgrader mengubah request HTTP menjadi WebSocket. Origin tidak diperiksa karena
+// CORS juga mengizinkan semua origin.
+var socketUpgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// socketMessage adalah pesan dari client
+type socketMessage struct {
+	Type           string          `json:"type" binding:"required,oneof=start pause resume step speed stop"`
+	Search         json.RawMessage `json:"search" binding:"required_if=Type start"`                                  // Untuk start: body yang sama dengan POST /api/search
+	StepsPerSecond *int            `json:"stepsPerSecond" binding:"required_if=Type speed,omitempty,min=0,max=1000"` // Untuk start dan speed: 0 berarti tanpa jeda
+}
+
+// socketState memberi tahu client keadaan sesi setelah start, pause, resume, speed dan stop
+type socketState struct {
+	Type           string `json:"type"`  // "state"
+	State          string `json:"state"` // running, paused atau idle
+	StepsPerSecond int    `json:"stepsPerSecond"`
+}
+
+// socketStep adalah satu langkah traversal
+type socketStep struct {
+	Type string `json:"type"` // "step"
+	Seq  int    `json:"seq"`  // Nomor langkah, mulai dari 1 untuk setiap pencarian
+	services.Step
+}
+
+// socketOutcome adalah hasil akhir pencarian ("result") atau pesan client yang ditolak
+// ("error"), dengan status, code, message, errors dan data seperti envelope
+type socketOutcome struct {
+	Type    string             `json:"type"`
+	Status  string             `json:"status"`
+	Code    string             `json:"code"`
+	Message string             `json:"message"`
+	Errors  []utils.FieldError `json:"errors,omitempty"`
+	Data    gin.H              `json:"data,omitempty"`
+}
+
+// SearchSocket membuat handler GET /api/ws/search. Client mengirim pesan start dengan
+// request pencarian, lalu menerima event step untuk setiap langkah traversal dan satu
+// event result di akhir. Pesan pause, resume, step, speed dan stop mengatur jalannya
+// pencarian. Satu koneksi menjalankan satu pencarian sekaligus.
+func SearchSocket(catalog *services.Catalog, players *services.PlayerStore, traces *services.TraceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !websocket.IsWebSocketUpgrade(c.Request) {
+			c.Header("Upgrade", "websocket")
+			utils.Error(c, http.StatusUpgradeRequired, utils.CodeInvalidRequest, "Expected a WebSocket connection")
+			return
+		}
+		conn, err := socketUpgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			return // Upgrader sudah mengirim response error
+		}
+		conn.SetReadLimit(socketReadLimit)
+
+		session := &searchSession{
+			conn:           conn,
+			catalog:        catalog,
+			players:        players,
+			traces:         traces,
+			strict:         utils.Strict(c), // ?strict=true menolak field yang tidak dikenal di search
+			stepsPerSecond: defaultStepsPerSecond,
+			changed:        make(chan struct{}),
+			quit:           make(chan struct{}),
+		}
+		defer session.close()
+		go session.ping()
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return // Client menutup koneksi
+			}
+			session.handle(data)
+		}
+	}
+}
+
+// searchSession adalah satu koneksi WebSocket. Pencarian berjalan di goroutine sendiri,
+// sementara handler terus membaca pesan client.
+type searchSession struct {
+	conn    *websocket.Conn
+	writeMu sync.Mutex // gorilla/websocket hanya mengizinkan satu penulis sekaligus
+	catalog *services.Catalog
+	players *services.PlayerStore
+	traces  *services.TraceStore
+	strict  bool
+	quit    chan struct{} // Ditutup saat koneksi selesai
+
+	mu             sync.Mutex
+	paused         bool
+	stepsPerSecond int
+	steps          int                // Langkah yang boleh jalan selama dijeda, dari pesan step
+	changed        chan struct{}      // Ditutup dan diganti setiap kali pengaturan berubah
+	cancel         context.CancelFunc // nil jika tidak ada pencarian yang berjalan
+	done           chan struct{}      // Ditutup saat goroutine pencarian terakhir selesai
+}
+
+// handle menjalankan satu pesan client
+func (s *searchSession) handle(data []byte) {
+	var message socketMessage
+	if err := json.Unmarshal(data, &message); err != nil {
+		code, text, errs := utils.DecodeError(err)
+		s.reject(code, text, errs...)
+		return
+	}
+	if errs := utils.ValidationErrors(&message); len(errs) > 0 {
+		s.reject(utils.CodeValidationFailed, "Request validation failed", errs...)
+		return
+	}
+
+	switch message.Type {
+	case "start":
+		s.start(message)
+	case "pause":
+		s.update(func() { s.paused = true })
+	case "resume":
+		s.update(func() { s.paused, s.steps = false, 0 })
+	case "step":
+		s.mu.Lock()
+		if s.paused {
+			s.steps++ // Satu langkah lagi, lalu tetap dijeda
+			s.wake()
+		}
+		s.mu.Unlock()
+	case "speed":
+		s.update(func() { s.stepsPerSecond = *message.StepsPerSecond })
+	case "stop":
+		s.stop()
+	}
+}
+
+// start memeriksa request pencarian lalu menjalankannya di goroutine baru. Request yang
+// ditolak dijawab dengan event result, sama seperti item batch yang ditolak.
+func (s *searchSession) start(message socketMessage) {
+	s.mu.Lock()
+	running, previous := s.cancel != nil, s.done
+	s.mu.Unlock()
+	if running {
+		s.reject(utils.CodeInvalidRequest, "A search is already running, send stop first")
+		return
+	}
+	if previous != nil {
+		<-previous // Event terakhir pencarian sebelumnya sudah terkirim
+	}
+
+	var request searchRequest
+	decoder := json.NewDecoder(bytes.NewReader(message.Search))
+	if s.strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&request); err != nil {
+		code, text, errs := utils.DecodeError(err)
+		s.finish(failedSearch(http.StatusBadRequest, code, text, searchFields(errs)...))
+		return
+	}
+	if errs := utils.ValidationErrors(&request); len(errs) > 0 {
+		s.finish(failedSearch(http.StatusBadRequest, utils.CodeValidationFailed, "Request validation failed", searchFields(errs)...))
+		return
+	}
+	prepared, failure := prepareSearch(s.catalog, s.players, s.traces, request)
+	if failure != nil {
+		s.finish(failure)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	s.update(func() {
+		s.cancel, s.done = cancel, done
+		s.paused, s.steps = false, 0
+		if message.StepsPerSecond != nil {
+			s.stepsPerSecond = *message.StepsPerSecond
+		}
+	})
+	go func() {
+		defer close(done)
+		outcome := s.run(ctx, prepared)
+		s.mu.Lock()
+		s.cancel = nil
+		s.mu.Unlock()
+		cancel()
+		if outcome == nil {
+			s.sendState() // Dihentikan lewat stop
+			return
+		}
+		s.finish(outcome)
+	}()
+}
+
+// run menjalankan pencarian dan mengirim setiap langkahnya. Hasilnya nil jika pencarian
+// dihentikan sebelum selesai.
+func (s *searchSession) run(ctx context.Context, prepared *preparedSearch) *searchOutcome {
+	request := prepared.request
+	seq := 0
+	var last time.Time
+	options := prepared.options()
+	options.OnStep = func(step services.Step) error {
+		if err := s.wait(ctx, &last); err != nil {
+			return err
+		}
+		seq++
+		return s.send(socketStep{Type: "step", Seq: seq, Step: step})
+	}
+
+	result, err := prepared.searcher.Search(request.Algorithm, request.ElementName, request.RecipeType, request.MaxRecipes, options)
+	if err != nil && ctx.Err() != nil {
+		return nil // Dihentikan lewat stop, atau koneksi putus
+	}
+	return prepared.outcome(result, err)
+}
+
+// wait menahan langkah berikutnya sesuai kecepatan, dan selama pencarian dijeda kecuali
+// client meminta satu langkah. last adalah waktu langkah sebelumnya.
+func (s *searchSession) wait(ctx context.Context, last *time.Time) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s.mu.Lock()
+		paused, changed := s.paused, s.changed
+		var interval time.Duration
+		if s.stepsPerSecond > 0 {
+			interval = time.Second / time.Duration(s.stepsPerSecond)
+		}
+		if paused && s.steps > 0 {
+			s.steps--
+			s.mu.Unlock()
+			*last = time.Now()
+			return nil
+		}
+		s.mu.Unlock()
+
+		if paused {
+			select {
+			case <-ctx.Done():
+			case <-changed:
+			}
+			continue
+		}
+		delay := interval - time.Since(*last)
+		if delay <= 0 {
+			*last = time.Now()
+			return nil
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-changed:
+			timer.Stop() // Kecepatan berubah atau dijeda, hitung ulang
+		case <-timer.C:
+			*last = time.Now()
+			return nil
+		}
+	}
+}
+
+// update mengubah pengaturan sesi, membangunkan pencarian yang sedang menunggu, lalu
+// mengirim state terbaru ke client
+func (s *searchSession) update(change func()) {
+	s.mu.Lock()
+	change()
+	s.wake()
+	s.mu.Unlock()
+	s.sendState()
+}
+
+// wake membangunkan pencarian yang sedang menunggu di wait. Dipanggil dengan mu terkunci.
+func (s *searchSession) wake() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// stop menghentikan pencarian yang berjalan dan menunggu goroutine-nya selesai
+func (s *searchSession) stop() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if cancel == nil {
+		s.sendState() // Tidak ada yang dihentikan
+		return
+	}
+	cancel()
+	<-done
+}
+
+// close menghentikan pencarian dan ping, lalu menutup koneksi
+func (s *searchSession) close() {
+	s.mu.Lock()
+	cancel, done := s.cancel, s.done
+	s.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+	if done != nil {
+		<-done
+	}
+	close(s.quit)
+	s.conn.Close()
+}
+
+// ping menjaga koneksi tetap hidup selama pencarian dijeda atau tidak ada pencarian
+func (s *searchSession) ping() {
+	ticker := time.NewTicker(socketPingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.quit:
+			return
+		case <-ticker.C:
+			if err := s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(socketWriteTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// send menulis satu event JSON
+func (s *searchSession) send(event interface{}) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.conn.SetWriteDeadline(time.Now().Add(socketWriteTimeout))
+	return s.conn.WriteJSON(event)
+}
+
+// sendState mengirim state sesi saat ini
+func (s *searchSession) sendState() {
+	s.mu.Lock()
+	state := socketState{Type: "state", State: "idle", StepsPerSecond: s.stepsPerSecond}
+	if s.cancel != nil {
+		state.State = "running"
+		if s.paused {
+			state.State = "paused"
+		}
+	}
+	s.mu.Unlock()
+	s.send(state)
+}
+
+// finish mengirim hasil akhir pencarian
+func (s *searchSession) finish(outcome *searchOutcome) {
+	s.send(socketOutcome{
+		Type:    "result",
+		Status:  outcome.status(),
+		Code:    outcome.code,
+		Message: outcome.message,
+		Errors:  outcome.errs,
+		Data:    outcome.data,
+	})
+}
+
+// reject mengirim error untuk pesan client yang tidak bisa dijalankan
+func (s *searchSession) reject(code string, message string, errs ...utils.FieldError) {
+	s.send(socketOutcome{Type: "error", Status: utils.StatusError, Code: code, Message: message, Errors: errs})
+}
+
+// searchFields menambahkan awalan search. ke nama field error request pencarian
+func searchFields(errs []utils.FieldError) []utils.FieldError {
+	for i := range errs {
+		if errs[i].Field == "" {
+			errs[i].Field = "search" // Seluruh search bukan objek
+		} else {
+			errs[i].Field = "search." + errs[i].Field
+		}
+	}
+	return errs
+}
@@ -0,0 +1,170 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"main/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// dialSearchSocket menjalankan server test dengan /api/ws/search dan membuka koneksi ke sana
+func dialSearchSocket(t *testing.T) *websocket.Conn {
+	t.Helper()
+	router := gin.New()
+	router.GET("/api/ws/search", SearchSocket(newFixtureCatalog(), nil, nil))
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/api/ws/search", nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// sendEvent menulis satu pesan client
+func sendEvent(t *testing.T, conn *websocket.Conn, message any) {
+	t.Helper()
+	if err := conn.WriteJSON(message); err != nil {
+		t.Fatalf("kirim pesan: %v", err)
+	}
+}
+
+// readEvent membaca satu event server, gagal jika tidak ada event dalam 5 detik
+func readEvent(t *testing.T, conn *websocket.Conn) map[string]any {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	var event map[string]any
+	if err := conn.ReadJSON(&event); err != nil {
+		t.Fatalf("baca event: %v", err)
+	}
+	return event
+}
+
+// readUntil membaca event sampai match bernilai true dan mengembalikan event itu
+func readUntil(t *testing.T, conn *websocket.Conn, match func(map[string]any) bool) map[string]any {
+	t.Helper()
+	for {
+		if event := readEvent(t, conn); match(event) {
+			return event
+		}
+	}
+}
+
+func TestSearchSocketRun(t *testing.T) {
+	conn := dialSearchSocket(t)
+	sendEvent(t, conn, gin.H{
+		"type":           "start",
+		"stepsPerSecond": 0,
+		"search":         gin.H{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All"},
+	})
+
+	if event := readEvent(t, conn); event["type"] != "state" || event["state"] != "running" {
+		t.Fatalf("event pertama = %v, ingin state running", event)
+	}
+	steps := 0
+	for {
+		event := readEvent(t, conn)
+		if event["type"] != "step" {
+			if event["type"] != "result" || event["code"] != "OK" {
+				t.Fatalf("event terakhir = %v, ingin result OK", event)
+			}
+			data, _ := event["data"].(map[string]any)
+			if results, _ := data["results"].([]any); len(results) != 2 {
+				t.Errorf("jumlah resep = %d, ingin 2", len(results))
+			}
+			break
+		}
+		steps++
+		if event["seq"] != float64(steps) {
+			t.Fatalf("seq = %v, ingin %d", event["seq"], steps)
+		}
+	}
+	if steps == 0 {
+		t.Error("tidak ada event step sebelum result")
+	}
+}
+
+func TestSearchSocketPauseAndStep(t *testing.T) {
+	conn := dialSearchSocket(t)
+
+	// Satu langkah per detik: langkah pertama langsung, langkah kedua menunggu jeda
+	sendEvent(t, conn, gin.H{
+		"type":           "start",
+		"stepsPerSecond": 1,
+		"search":         gin.H{"elementName": "Brick", "algorithm": "DFS", "recipeType": "All"},
+	})
+	readUntil(t, conn, func(event map[string]any) bool { return event["type"] == "step" })
+
+	sendEvent(t, conn, gin.H{"type": "pause"})
+	readUntil(t, conn, func(event map[string]any) bool { return event["type"] == "state" && event["state"] == "paused" })
+
+	for _, seq := range []float64{2, 3} {
+		sendEvent(t, conn, gin.H{"type": "step"})
+		if event := readEvent(t, conn); event["type"] != "step" || event["seq"] != seq {
+			t.Fatalf("event setelah step = %v, ingin step dengan seq %v", event, seq)
+		}
+	}
+
+	sendEvent(t, conn, gin.H{"type": "stop"})
+	if event := readEvent(t, conn); event["type"] != "state" || event["state"] != "idle" {
+		t.Fatalf("event setelah stop = %v, ingin state idle", event)
+	}
+}
+
+func TestSearchSocketRejected(t *testing.T) {
+	tests := []struct {
+		name      string
+		message   any
+		wantType  string
+		wantCode  string
+		wantField string
+	}{
+		{name: "tipe pesan tidak dikenal", message: gin.H{"type": "fly"}, wantType: "error", wantCode: utils.CodeValidationFailed, wantField: "type"},
+		{name: "start tanpa search", message: gin.H{"type": "start"}, wantType: "error", wantCode: utils.CodeValidationFailed, wantField: "search"},
+		{name: "search tidak valid", message: gin.H{"type": "start", "search": gin.H{"elementName": "Brick", "algorithm": "A*", "recipeType": "One"}}, wantType: "result", wantCode: utils.CodeValidationFailed, wantField: "search.algorithm"},
+		{name: "stop tanpa pencarian", message: gin.H{"type": "stop"}, wantType: "state"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn := dialSearchSocket(t)
+			sendEvent(t, conn, tt.message)
+			event := readEvent(t, conn)
+			if event["type"] != tt.wantType {
+				t.Fatalf("event = %v, ingin type %s", event, tt.wantType)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			if event["code"] != tt.wantCode {
+				t.Errorf("code = %v, ingin %s", event["code"], tt.wantCode)
+			}
+			errs, _ := event["errors"].([]any)
+			if len(errs) == 0 {
+				t.Fatalf("event tanpa errors: %v", event)
+			}
+			if field := errs[0].(map[string]any)["field"]; field != tt.wantField {
+				t.Errorf("field = %v, ingin %s", field, tt.wantField)
+			}
+		})
+	}
+}
+
+func TestSearchSocketRequiresUpgrade(t *testing.T) {
+	router := gin.New()
+	router.GET("/api/ws/search", SearchSocket(newFixtureCatalog(), nil, nil))
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/ws/search", nil))
+	if rec.Code != http.StatusUpgradeRequired {
+		t.Errorf("status HTTP = %d, ingin 426", rec.Code)
+	}
+}
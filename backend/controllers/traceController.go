@@ -0,0 +1,106 @@
+// File ini berisi controller untuk rekaman traversal pencarian (trace).
+// Trace dibuat oleh pencarian dengan "trace": true, disimpan di memori, dan bisa
+// diunduh sebagai JSON atau diputar ulang sebagai NDJSON, satu langkah per baris.
+// Hanya DefaultTraceCapacity trace terbaru yang disimpan dan semuanya hilang saat
+// backend restart, jadi ID trace lama dijawab 404.
+
+package controllers
+
+import (
+	"main/services" // Import penyimpanan trace
+	"main/utils"    // Import envelope response standar
+	"net/http"      // Untuk kebutuhan HTTP response
+	"slices"        // Untuk memeriksa jenis langkah
+	"strings"       // Untuk menyusun pesan error
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// traceQuery adalah parameter GET /api/traces/:id. kinds boleh diulang atau dipisah koma.
+type traceQuery struct {
+	Kinds    []string `form:"kinds"`                                      // Jenis langkah yang dikirim -- kosong berarti semua
+	From     int      `form:"from" binding:"omitempty,min=1"`             // Nomor langkah (seq) pertama yang dikirim
+	Limit    int      `form:"limit" binding:"omitempty,min=1,max=200000"` // Jumlah langkah maksimal
+	Download bool     `form:"download"`                                   // Kirim sebagai file lampiran
+}
+
+// traceResponse adalah data response trace: ringkasan pencarian dan langkah yang dipilih.
+// Bentuknya sama dengan JSON services.Trace, jadi file unduhan bisa dibaca lagi oleh CLI.
+type traceResponse struct {
+	services.TraceSummary
+	Steps []services.TraceStep `json:"steps"`
+}
+
+// traceLine adalah satu baris NDJSON berisi satu langkah trace
+type traceLine struct {
+	Type string `json:"type"` // "step"
+	services.TraceStep
+}
+
+// traceSummaryLine adalah baris terakhir NDJSON trace
+type traceSummaryLine struct {
+	Type string `json:"type"` // "summary"
+	services.TraceSummary
+}
+
+// GetTrace membuat handler GET /api/traces/:id. Trace dikirim dalam envelope, atau
+// diputar ulang satu langkah per baris jika client meminta NDJSON lewat header Accept.
+func GetTrace(traces *services.TraceStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var query traceQuery
+		if !utils.BindQuery(c, &query, utils.Strict(c)) {
+			return // Error 400 sudah dikirim beserta parameter yang salah
+		}
+		kinds := splitValues(query.Kinds)
+		for _, kind := range kinds {
+			if !slices.Contains(services.StepKinds, services.StepKind(kind)) {
+				names := make([]string, len(services.StepKinds))
+				for i, known := range services.StepKinds {
+					names[i] = string(known)
+				}
+				utils.ValidationFailed(c, []utils.FieldError{{Field: "kinds", Message: "must be one of " + strings.Join(names, ", ")}})
+				return
+			}
+		}
+
+		trace, ok := traces.Get(c.Param("id"))
+		if !ok {
+			utils.Error(c, http.StatusNotFound, utils.CodeTraceNotFound, "Unknown trace, it may have been dropped for newer traces or lost when the server restarted")
+			return
+		}
+		steps := selectSteps(trace, kinds, query.From, query.Limit)
+
+		if utils.WantsNDJSON(c) {
+			write := utils.StartNDJSON(c)
+			for _, step := range steps {
+				if write(traceLine{Type: "step", TraceStep: step}) != nil {
+					return // Client putus
+				}
+			}
+			write(traceSummaryLine{Type: "summary", TraceSummary: trace.TraceSummary})
+			return
+		}
+
+		if query.Download {
+			c.Header("Content-Disposition", `attachment; filename="trace-`+trace.ID+`.json"`)
+		}
+		utils.Success(c, traceResponse{TraceSummary: trace.TraceSummary, Steps: steps})
+	}
+}
+
+// selectSteps mengambil langkah trace dengan jenis di kinds (semua jika kosong), mulai
+// dari nomor langkah from, paling banyak limit langkah (semua jika 0)
+func selectSteps(trace *services.Trace, kinds []string, from int, limit int) []services.TraceStep {
+	steps := []services.TraceStep{}
+	for i := 0; i < trace.Len(); i++ {
+		if limit > 0 && len(steps) == limit {
+			break
+		}
+		step := trace.Step(i)
+		if step.Seq < from || (len(kinds) > 0 && !slices.Contains(kinds, string(step.Kind))) {
+			continue
+		}
+		steps = append(steps, step)
+	}
+	return steps
+}
@@ -0,0 +1,159 @@
+package controllers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"main/services"
+	"main/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTraceRouter membuat router dengan POST /api/search dan GET /api/traces/:id
+// yang memakai penyimpanan trace yang sama
+func newTraceRouter() *gin.Engine {
+	catalog := newFixtureCatalog()
+	traces := services.NewTraceStore(services.DefaultTraceCapacity)
+	router := gin.New()
+	router.POST("/api/search", SearchRecipe(catalog, nil, traces))
+	router.GET("/api/traces/:id", GetTrace(traces))
+	return router
+}
+
+// recordTrace menjalankan pencarian dengan "trace": true dan mengembalikan ID trace-nya
+func recordTrace(t *testing.T, router http.Handler) string {
+	t.Helper()
+	rec := postJSON(t, router, "/api/search", map[string]any{"elementName": "Brick", "algorithm": "BFS", "recipeType": "All", "trace": true})
+	data, _ := decodeJSON(t, rec)["data"].(map[string]any)
+	id, _ := data["traceId"].(string)
+	if id == "" {
+		t.Fatalf("response tanpa traceId: %s", rec.Body.String())
+	}
+	return id
+}
+
+// getTrace mengirim GET /api/traces/... dengan header Accept opsional
+func getTrace(router http.Handler, target string, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestGetTrace(t *testing.T) {
+	router := newTraceRouter()
+	id := recordTrace(t, router)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		check      func(t *testing.T, steps []any)
+	}{
+		{
+			name:       "semua langkah",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, steps []any) {
+				if len(steps) == 0 {
+					t.Fatal("trace tanpa langkah")
+				}
+				for i, step := range steps {
+					if seq := step.(map[string]any)["seq"]; seq != float64(i+1) {
+						t.Fatalf("langkah %d: seq = %v, ingin %d", i, seq, i+1)
+					}
+				}
+			},
+		},
+		{
+			name:       "satu jenis langkah",
+			query:      "?kinds=recipe",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, steps []any) {
+				if len(steps) != 2 {
+					t.Fatalf("jumlah langkah recipe = %d, ingin 2", len(steps))
+				}
+				for _, step := range steps {
+					if kind := step.(map[string]any)["kind"]; kind != "recipe" {
+						t.Errorf("kind = %v, ingin recipe", kind)
+					}
+				}
+			},
+		},
+		{
+			name:       "from dan limit",
+			query:      "?from=3&limit=2",
+			wantStatus: http.StatusOK,
+			check: func(t *testing.T, steps []any) {
+				if len(steps) != 2 || steps[0].(map[string]any)["seq"] != float64(3) {
+					t.Errorf("langkah = %v, ingin 2 langkah mulai seq 3", steps)
+				}
+			},
+		},
+		{name: "jenis langkah tidak dikenal", query: "?kinds=fly", wantStatus: http.StatusBadRequest},
+		{name: "limit nol berarti semua", query: "?limit=0", wantStatus: http.StatusOK},
+		{name: "limit negatif", query: "?limit=-1", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := getTrace(router, "/api/traces/"+id+tt.query, "")
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status HTTP = %d, ingin %d\n%s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.check == nil {
+				return
+			}
+			data, _ := decodeJSON(t, rec)["data"].(map[string]any)
+			if data["id"] != id || data["element"] != "Brick" || data["algorithm"] != "BFS" {
+				t.Errorf("ringkasan trace = %v, ingin trace %s untuk Brick dengan BFS", data, id)
+			}
+			steps, _ := data["steps"].([]any)
+			tt.check(t, steps)
+		})
+	}
+}
+
+func TestGetTraceNotFound(t *testing.T) {
+	rec := getTrace(newTraceRouter(), "/api/traces/tidak-ada", "")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status HTTP = %d, ingin 404", rec.Code)
+	}
+	if code := decodeJSON(t, rec)["code"]; code != utils.CodeTraceNotFound {
+		t.Errorf("code = %v, ingin %s", code, utils.CodeTraceNotFound)
+	}
+}
+
+func TestGetTraceNDJSON(t *testing.T) {
+	router := newTraceRouter()
+	id := recordTrace(t, router)
+
+	rec := getTrace(router, "/api/traces/"+id+"?kinds=visit", utils.NDJSONContentType)
+	lines := readNDJSON(t, rec)
+	if len(lines) < 2 {
+		t.Fatalf("jumlah baris = %d, ingin langkah dan ringkasan\n%s", len(lines), rec.Body.String())
+	}
+	for _, line := range lines[:len(lines)-1] {
+		if line["type"] != "step" || line["kind"] != "visit" {
+			t.Errorf("baris = %v, ingin langkah visit", line)
+		}
+	}
+	if summary := lines[len(lines)-1]; summary["type"] != "summary" || summary["id"] != id {
+		t.Errorf("baris terakhir = %v, ingin ringkasan trace %s", summary, id)
+	}
+}
+
+func TestGetTraceDownload(t *testing.T) {
+	router := newTraceRouter()
+	id := recordTrace(t, router)
+
+	rec := getTrace(router, "/api/traces/"+id+"?download=true", "")
+	if disposition := rec.Header().Get("Content-Disposition"); !strings.Contains(disposition, "trace-"+id+".json") {
+		t.Errorf("Content-Disposition = %q, ingin lampiran trace-%s.json", disposition, id)
+	}
+}
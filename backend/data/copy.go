@@ -0,0 +1,40 @@
+//go:build ignore
+
+// Program ini dijalankan lewat "go generate ./data" untuk menyalin dataset
+// dari folder database ke folder ini sebelum build dengan tag "embed".
+package main
+
+import (
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+)
+
+func main() {
+	for _, name := range []string{"alchemy.db", "mapper2.json"} {
+		if err := copyFile(filepath.Join("..", "..", "database", name), name); err != nil {
+			log.Fatalf("Gagal menyalin %s: %v", name, err)
+		}
+		log.Printf("Disalin: %s", name)
+	}
+}
+
+// copyFile menyalin isi file src ke dst
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.Create(dst)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
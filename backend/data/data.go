@@ -0,0 +1,26 @@
+// Package data menyimpan salinan dataset resep (snapshot SQLite dan mapper gambar)
+// yang bisa di-embed ke dalam binary backend.
+//
+// Secara default binary dibangun tanpa data embed dan membaca file dari folder database.
+// Untuk membangun binary mandiri, salin dataset lalu build dengan tag "embed":
+//
+//	go generate ./data
+//	go build -tags embed -o main .
+package data
+
+//go:generate go run copy.go
+
+// SQLite mengembalikan isi snapshot alchemy.db yang di-embed (nil jika tidak di-embed)
+func SQLite() []byte {
+	return sqliteSnapshot
+}
+
+// Mapper mengembalikan isi mapper2.json yang di-embed (nil jika tidak di-embed)
+func Mapper() []byte {
+	return mapperJSON
+}
+
+// Embedded bernilai true jika binary dibangun dengan tag "embed"
+func Embedded() bool {
+	return len(sqliteSnapshot) > 0 && len(mapperJSON) > 0
+}
@@ -0,0 +1,11 @@
+//go:build embed
+
+package data
+
+import _ "embed" // Untuk directive go:embed
+
+//go:embed alchemy.db
+var sqliteSnapshot []byte
+
+//go:embed mapper2.json
+var mapperJSON []byte
@@ -0,0 +1,9 @@
+//go:build !embed
+
+package data
+
+// Tanpa tag "embed" tidak ada data yang disertakan di binary
+var (
+	sqliteSnapshot []byte
+	mapperJSON     []byte
+)
@@ -0,0 +1,30 @@
+// Package docs menyajikan dokumen OpenAPI backend dan halaman dokumentasinya.
+// Keduanya disertakan ke binary, jadi halaman tetap bisa dibuka tanpa internet.
+package docs
+
+import (
+	_ "embed"  // Untuk menyertakan openapi.json dan index.html
+	"net/http" // Untuk kebutuhan HTTP response
+
+	"github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// OpenAPISpec adalah dokumen OpenAPI 3 semua endpoint backend
+//
+//go:embed openapi.json
+var OpenAPISpec []byte
+
+// page adalah halaman HTML yang membaca /api/openapi.json dan menampilkannya
+//
+//go:embed index.html
+var page []byte
+
+// OpenAPI adalah handler yang menyajikan dokumen OpenAPI
+func OpenAPI(c *gin.Context) {
+	c.Data(http.StatusOK, "application/json; charset=utf-8", OpenAPISpec)
+}
+
+// Page adalah handler halaman dokumentasi API
+func Page(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+}
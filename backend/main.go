@@ -5,7 +5,11 @@ package main
 
 import (
     "github.com/gin-gonic/gin" // Framework web Gin
+    "log"                      // Untuk logging error startup
     "main/controllers"         // Import controller pencarian resep
+    "main/docs"                // Import dokumen OpenAPI dan halaman dokumentasi
+    "main/services"            // Import repository dan searcher resep
+    "main/utils"               // Import envelope response dan middleware request ID
     "net/http"                 // Untuk kebutuhan HTTP
 )
 
@@ -13,7 +17,8 @@ func CORSMiddleware() gin.HandlerFunc {
     return func(c *gin.Context) {
         c.Writer.Header().Set("Access-Control-Allow-Origin", "*") // Izinkan semua origin (untuk pengembangan)
         c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE") // Metode yang diizinkan
-        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization") // Header yang diizinkan
+        c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, If-None-Match") // Header yang diizinkan
+        c.Writer.Header().Set("Access-Control-Expose-Headers", "X-Request-ID, ETag") // Header yang boleh dibaca frontend
         c.Writer.Header().Set("Access-Control-Allow-Credentials", "true") // Izinkan kredensial
 
         if c.Request.Method == "OPTIONS" { // Tangani preflight request CORS
@@ -26,8 +31,40 @@ func CORSMiddleware() gin.HandlerFunc {
 } // ye intinya ini cuek aja lah 
 
 func main() {
+    catalog, err := services.OpenDefaultCatalog() // Buka semua dataset resep (file atau embed)
+    if err != nil {
+        log.Fatalf("Gagal membuka dataset: %v", err)
+    }
+
+    players, err := services.OpenDefaultPlayerStore() // Profil pemain di players.db, di samping alchemy.db
+    if err != nil {
+        log.Printf("Profil pemain dinonaktifkan: %v", err)
+        players = nil
+    }
+
+    traces := services.NewTraceStore(services.DefaultTraceCapacity) // Trace pencarian terbaru, hanya di memori
+
     r := gin.Default() // Inisialisasi Gin
     r.Use(CORSMiddleware()) // Pasang middleware CORS
-    r.POST("/api/search", controllers.SearchRecipe) // Endpoint pencarian resep
+    r.Use(utils.RequestID()) // Setiap response membawa request ID
+    r.NoRoute(utils.NoRoute) // Route yang tidak ada tetap dibalas dengan envelope
+    r.GET("/api/schema/response.json", utils.Schema) // JSON Schema envelope response
+    r.GET("/api/openapi.json", docs.OpenAPI) // Dokumen OpenAPI 3 semua endpoint
+    r.GET("/api/docs", docs.Page) // Halaman dokumentasi API
+    r.POST("/api/search", controllers.SearchRecipe(catalog, players, traces)) // Endpoint pencarian resep
+    r.GET("/api/search", controllers.SearchRecipeQuery(catalog, players, traces)) // Pencarian lewat URL, bisa di-bookmark dan di-cache
+    r.POST("/api/search/batch", controllers.SearchBatch(catalog, players, traces)) // Banyak pencarian sekaligus, bisa dialirkan sebagai NDJSON
+    r.GET("/api/ws/search", controllers.SearchSocket(catalog, players, traces)) // WebSocket untuk melihat traversal langkah demi langkah
+    r.GET("/api/traces/:id", controllers.GetTrace(traces)) // Rekaman traversal pencarian dengan "trace": true
+    r.GET("/api/datasets", controllers.ListDatasets(catalog)) // Daftar dataset yang tersedia
+    r.GET("/api/elements/suggest", controllers.SuggestElements(catalog)) // Autocomplete nama elemen
+    r.GET("/api/plan/full", controllers.FullGamePlan(catalog)) // Urutan kombinasi untuk membuka semua elemen
+    r.POST("/api/hints", controllers.SuggestHints(catalog, players)) // Saran kombinasi berikutnya untuk pemain
+    if players != nil {
+        r.GET("/api/players/:id", controllers.GetPlayer(players)) // Profil pemain beserta penemuannya
+        r.POST("/api/players/:id/import", controllers.ImportPlayer(catalog, players)) // Impor daftar elemen yang sudah ditemukan
+        r.POST("/api/players/:id/discoveries", controllers.DiscoverElement(catalog, players)) // Catat hasil menggabungkan dua elemen
+        r.GET("/api/players/:id/progress", controllers.PlayerProgress(catalog, players)) // Persentase penyelesaian per tier
+    }
     r.Run(":8081") // Jalankan server di port 8081
 }
\ No newline at end of file
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"sort"
+)
+
+// DefaultDataset is the Little Alchemy 2 dataset, searched when a request names none
+const DefaultDataset = "la2"
+
+// Catalog holds the named datasets (Little Alchemy 2, Little Alchemy 1, Myths and
+// Monsters, ...) together with a searcher for each of them
+type Catalog struct {
+	searchers map[string]*Searcher
+}
+
+// NewCatalog creates an empty catalog
+func NewCatalog() *Catalog {
+	return &Catalog{searchers: make(map[string]*Searcher)}
+}
+
+// Add registers a dataset under name, replacing any dataset with the same name
+func (c *Catalog) Add(name string, repo RecipeRepository) {
+	c.searchers[name] = NewSearcher(repo)
+}
+
+// Names returns the dataset names in alphabetical order
+func (c *Catalog) Names() []string {
+	names := make([]string, 0, len(c.searchers))
+	for name := range c.searchers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Default returns the dataset used when none is named: DefaultDataset when present,
+// otherwise the first dataset by name
+func (c *Catalog) Default() string {
+	if _, ok := c.searchers[DefaultDataset]; ok {
+		return DefaultDataset
+	}
+	if names := c.Names(); len(names) > 0 {
+		return names[0]
+	}
+	return ""
+}
+
+// Searcher returns the searcher of a dataset. An empty name selects the default dataset.
+func (c *Catalog) Searcher(name string) (*Searcher, bool) {
+	if name == "" {
+		name = c.Default()
+	}
+	searcher, ok := c.searchers[name]
+	return searcher, ok
+}
+
+// Repository returns the recipe repository of a dataset. An empty name selects the default dataset.
+func (c *Catalog) Repository(name string) (RecipeRepository, bool) {
+	searcher, ok := c.Searcher(name)
+	if !ok {
+		return nil, false
+	}
+	return searcher.Repository(), true
+}
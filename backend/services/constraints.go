@@ -0,0 +1,70 @@
+package services
+
+import "strings"
+
+// constraints holds the exclude and require rules of a search in a form the
+// traversal can check per combination
+type constraints struct {
+	elements     map[string]bool
+	combinations map[Combination]bool
+	required     []string
+}
+
+// newConstraints parses the exclude and require lists of the search options
+func newConstraints(opts SearchOptions) *constraints {
+	rules := &constraints{
+		elements:     make(map[string]bool),
+		combinations: make(map[Combination]bool),
+	}
+	for _, entry := range opts.Exclude {
+		if item1, item2, ok := strings.Cut(entry, "+"); ok {
+			rules.combinations[orderedCombination(strings.TrimSpace(item1), strings.TrimSpace(item2))] = true
+		} else if entry = strings.TrimSpace(entry); entry != "" {
+			rules.elements[entry] = true
+		}
+	}
+	for _, element := range opts.Require {
+		if element = strings.TrimSpace(element); element != "" {
+			rules.required = append(rules.required, element)
+		}
+	}
+	return rules
+}
+
+// orderedCombination puts the ingredients in a fixed order so A + B matches B + A
+func orderedCombination(item1, item2 string) Combination {
+	if item2 < item1 {
+		item1, item2 = item2, item1
+	}
+	return Combination{Item1: item1, Item2: item2}
+}
+
+// excludes reports whether element must not appear in a recipe tree
+func (c *constraints) excludes(element string) bool {
+	return c.elements[element]
+}
+
+// allows reports whether a combination may be used while expanding a recipe
+func (c *constraints) allows(combo Combination) bool {
+	if c.elements[combo.Item1] || c.elements[combo.Item2] {
+		return false
+	}
+	return !c.combinations[orderedCombination(combo.Item1, combo.Item2)]
+}
+
+// satisfied reports whether a complete recipe contains every required element
+func (c *constraints) satisfied(recipe []RecipeStep) bool {
+	for _, element := range c.required {
+		found := false
+		for _, step := range recipe {
+			if step.Result == element || step.Item1 == element || step.Item2 == element {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSearchConstraints(t *testing.T) {
+	tests := []struct {
+		name    string
+		exclude []string
+		require []string
+		status  SearchStatus
+		recipes []string // First line of each recipe, in order
+	}{
+		{name: "none", status: StatusOK, recipes: []string{"Brick = Mud + Fire", "Brick = Stone + Fire"}},
+		{name: "exclude element", exclude: []string{"Mud"}, status: StatusOK, recipes: []string{"Brick = Stone + Fire"}},
+		{name: "exclude nested element", exclude: []string{"Lava"}, status: StatusOK, recipes: []string{"Brick = Mud + Fire"}},
+		{name: "exclude combination either way", exclude: []string{"Fire + Stone"}, status: StatusOK, recipes: []string{"Brick = Mud + Fire"}},
+		{name: "require element", require: []string{"Air"}, status: StatusOK, recipes: []string{"Brick = Stone + Fire"}},
+		{name: "require and exclude", exclude: []string{"Lava"}, require: []string{"Water"}, status: StatusOK, recipes: []string{"Brick = Mud + Fire"}},
+		{name: "excluded everywhere", exclude: []string{"Fire"}, status: StatusNoRecipe},
+		{name: "required but unused", require: []string{"Steam"}, status: StatusNoRecipe},
+	}
+
+	searcher := newFixtureSearcher()
+	for _, algorithm := range Algorithms {
+		for _, tt := range tests {
+			t.Run(algorithm+"/"+tt.name, func(t *testing.T) {
+				result, err := searcher.Search(algorithm, "Brick", "All", 0, SearchOptions{Exclude: tt.exclude, Require: tt.require})
+				if err != nil {
+					t.Fatalf("Search error: %v", err)
+				}
+				if result.Status != tt.status {
+					t.Errorf("status = %s, want %s", result.Status, tt.status)
+				}
+				var recipes []string
+				if HasRecipe(result.Results) {
+					for _, tree := range result.Results {
+						recipes = append(recipes, tree.Recipe[0])
+					}
+				}
+				if !slices.Equal(recipes, tt.recipes) {
+					t.Errorf("recipes = %q, want %q", recipes, tt.recipes)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+)
+
+// LoadCSVRepository reads the scraper's alchemy.csv (Element,Item1,Item2) into an
+// in-memory repository. The header row is optional, rows with empty item columns
+// are basic elements, and malformed lines are logged and skipped.
+func LoadCSVRepository(r io.Reader, mapper map[string]string) (*MemoryRepository, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // Field count is checked per row below
+	reader.TrimLeadingSpace = true
+
+	var rows []ElementRow
+	skipped := 0
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, fmt.Errorf("gagal membaca dataset CSV: %w", err)
+			}
+			log.Printf("Melewati baris CSV %d: %v", parseErr.StartLine, err)
+			skipped++
+			continue
+		}
+
+		if line == 1 && len(record) > 0 {
+			record[0] = strings.TrimPrefix(record[0], "\ufeff") // Byte order mark dari Excel
+			if isCSVHeader(record) {
+				continue
+			}
+		}
+
+		row, ok := parseCSVRow(record)
+		if !ok {
+			start, _ := reader.FieldPos(0)
+			log.Printf("Melewati baris CSV %d: format tidak valid %q", start, record)
+			skipped++
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("dataset CSV tidak berisi baris yang valid (%d baris dilewati)", skipped)
+	}
+	return NewMemoryRepository(rows, mapper), nil
+}
+
+// isCSVHeader reports whether the record is the Element,Item1,Item2 header
+func isCSVHeader(record []string) bool {
+	return len(record) == 3 &&
+		strings.EqualFold(strings.TrimSpace(record[0]), "Element") &&
+		strings.EqualFold(strings.TrimSpace(record[1]), "Item1") &&
+		strings.EqualFold(strings.TrimSpace(record[2]), "Item2")
+}
+
+// parseCSVRow converts a record into an element row. A valid row has an element
+// name and either both items (a recipe) or none (a basic element).
+func parseCSVRow(record []string) (ElementRow, bool) {
+	if len(record) != 1 && len(record) != 3 {
+		return ElementRow{}, false
+	}
+
+	row := ElementRow{Element: strings.TrimSpace(record[0])}
+	if len(record) == 3 {
+		row.Item1 = strings.TrimSpace(record[1])
+		row.Item2 = strings.TrimSpace(record[2])
+	}
+
+	if row.Element == "" || (row.Item1 == "") != (row.Item2 == "") {
+		return ElementRow{}, false
+	}
+	return row, true
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLoadCSVRepository(t *testing.T) {
+	tests := []struct {
+		name     string
+		csv      string
+		elements []string
+		basic    []string
+	}{
+		{
+			name:     "header with byte order mark",
+			csv:      "\ufeffElement,Item1,Item2\nFire,,\nWater,,\nSteam,Water,Fire\n",
+			elements: []string{"Fire", "Water", "Steam"},
+			basic:    []string{"Fire", "Water"},
+		},
+		{
+			name:     "without header",
+			csv:      "Fire\nWater\nSteam, Water, Fire\n",
+			elements: []string{"Fire", "Water", "Steam"},
+			basic:    []string{"Fire", "Water"},
+		},
+		{
+			name:     "bad rows skipped",
+			csv:      "Element,Item1,Item2\nFire,,\nWater,,\nSteam,Water\n,Water,Fire\nMud,Water,\nSteam,Water,Fire\n\"Lava,Earth,Fire\n",
+			elements: []string{"Fire", "Water", "Steam"},
+			basic:    []string{"Fire", "Water"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, err := LoadCSVRepository(strings.NewReader(tt.csv), nil)
+			if err != nil {
+				t.Fatalf("LoadCSVRepository error: %v", err)
+			}
+			for _, element := range tt.elements {
+				if exists, _ := repo.ElementExists(element); !exists {
+					t.Errorf("element %s is missing", element)
+				}
+			}
+			for _, element := range []string{"Mud", "Lava"} {
+				if exists, _ := repo.ElementExists(element); exists {
+					t.Errorf("element %s from a bad row was loaded", element)
+				}
+			}
+			if basic, _ := repo.BasicElements(); !reflect.DeepEqual(basic, tt.basic) {
+				t.Errorf("basic elements = %q, want %q", basic, tt.basic)
+			}
+			recipes, _ := repo.Recipes("Steam")
+			if want := []Combination{{Item1: "Water", Item2: "Fire"}}; !reflect.DeepEqual(recipes, want) {
+				t.Errorf("recipes of Steam = %v, want %v", recipes, want)
+			}
+		})
+	}
+}
+
+func TestLoadCSVRepositoryNoValidRows(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{name: "empty", csv: ""},
+		{name: "header only", csv: "Element,Item1,Item2\n"},
+		{name: "only bad rows", csv: "Element,Item1,Item2\nSteam,Water\n,Water,Fire\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadCSVRepository(strings.NewReader(tt.csv), nil); err == nil {
+				t.Error("LoadCSVRepository succeeded, want an error for a dataset without valid rows")
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package services
+
+// MinDepths computes the minimum recipe depth of every element reachable from the
+// basic elements. Basic elements have depth 0 and an element made from a recipe
+// has depth 1 + the deeper of its two ingredients. Unreachable elements are left out.
+func MinDepths(repo RecipeRepository) (map[string]int, error) {
+	elements, err := repo.Elements()
+	if err != nil {
+		return nil, err
+	}
+	basicElements, err := repo.BasicElements()
+	if err != nil {
+		return nil, err
+	}
+
+	recipes := make(map[string][]Combination, len(elements))
+	for _, element := range elements {
+		if recipes[element], err = repo.Recipes(element); err != nil {
+			return nil, err
+		}
+	}
+
+	depths := make(map[string]int, len(elements))
+	for _, basic := range basicElements {
+		depths[basic] = 0
+	}
+
+	// Relax every recipe until no depth improves (at most one round per tier)
+	for changed := true; changed; {
+		changed = false
+		for _, element := range elements {
+			for _, combo := range recipes[element] {
+				depth1, ok1 := depths[combo.Item1]
+				depth2, ok2 := depths[combo.Item2]
+				if !ok1 || !ok2 {
+					continue
+				}
+				depth := 1 + max(depth1, depth2)
+				if current, ok := depths[element]; !ok || depth < current {
+					depths[element] = depth
+					changed = true
+				}
+			}
+		}
+	}
+
+	return depths, nil
+}
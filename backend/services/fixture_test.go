@@ -0,0 +1,33 @@
+package services
+
+// fixtureRows is a small recipe graph shared by the tests of this package:
+//
+//	Mud   = Water + Earth
+//	Lava  = Earth + Fire
+//	Stone = Lava + Air
+//	Brick = Mud + Fire | Stone + Fire
+//	Steam = Water + Fire
+//	Ghost = Ghost + Ghost (no complete recipe)
+var fixtureRows = []ElementRow{
+	{Element: "Air"},
+	{Element: "Earth"},
+	{Element: "Fire"},
+	{Element: "Water"},
+	{Element: "Mud", Item1: "Water", Item2: "Earth"},
+	{Element: "Lava", Item1: "Earth", Item2: "Fire"},
+	{Element: "Stone", Item1: "Lava", Item2: "Air"},
+	{Element: "Brick", Item1: "Mud", Item2: "Fire"},
+	{Element: "Brick", Item1: "Stone", Item2: "Fire"},
+	{Element: "Steam", Item1: "Water", Item2: "Fire"},
+	{Element: "Ghost", Item1: "Ghost", Item2: "Ghost"},
+}
+
+// newFixtureRepository returns a fresh in-memory repository over fixtureRows
+func newFixtureRepository() *MemoryRepository {
+	return NewMemoryRepository(fixtureRows, nil)
+}
+
+// newFixtureSearcher returns a searcher over a fresh fixture repository
+func newFixtureSearcher() *Searcher {
+	return NewSearcher(newFixtureRepository())
+}
@@ -0,0 +1,140 @@
+package services
+
+import "sort"
+
+// FullPlan is an order of combinations that discovers every element reachable
+// from the basic elements, starting with only the basic elements
+type FullPlan struct {
+	Steps       []FullPlanStep   `json:"steps"`
+	Checkpoints []TierCheckpoint `json:"checkpoints"`
+	Basic       int              `json:"basic"`       // Elements known before the first step
+	Discovered  int              `json:"discovered"`  // Elements known after the last step, basics included
+	Unreachable []string         `json:"unreachable"` // Elements no recipe reaches from the basics
+}
+
+// FullPlanStep is one combination of the plan. A combination can create several
+// elements at once, so it lists every element it discovers.
+type FullPlanStep struct {
+	Item1     string   `json:"item1"`
+	Item2     string   `json:"item2"`
+	Discovers []string `json:"discovers"`
+	Tier      int      `json:"tier"`
+}
+
+// TierCheckpoint summarises the plan once every element of a tier is discovered
+type TierCheckpoint struct {
+	Tier          int `json:"tier"`
+	Steps         int `json:"steps"`         // Combinations made in this tier
+	TotalSteps    int `json:"totalSteps"`    // Combinations made up to and including this tier
+	Elements      int `json:"elements"`      // Elements discovered in this tier
+	TotalElements int `json:"totalElements"` // Elements known after this tier, basics included
+}
+
+// PlanFullGame computes a full-game unlock order on an in-memory dataset. Every
+// step combines two already discovered elements.
+//
+// Elements are unlocked tier by tier (see MinDepths): once every element of the
+// lower tiers is known, each element of the next tier is one combination away.
+// Within a tier the planner greedily picks the combination that discovers the
+// most new elements, so the step count never exceeds the number of elements to
+// discover and drops below it where a combination creates several elements.
+func PlanFullGame(repo *MemoryRepository) (*FullPlan, error) {
+	depths, err := MinDepths(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group the recipes by combination, keeping the dataset order for stable output
+	var combinations []Combination
+	results := make(map[Combination][]string)
+	tiers := make(map[int][]string)
+	plan := &FullPlan{Steps: []FullPlanStep{}, Checkpoints: []TierCheckpoint{}, Unreachable: []string{}}
+	for _, element := range repo.order {
+		depth, ok := depths[element]
+		if !ok {
+			plan.Unreachable = append(plan.Unreachable, element)
+			continue
+		}
+		if depth == 0 {
+			plan.Basic++
+			continue
+		}
+		tiers[depth] = append(tiers[depth], element)
+
+		for _, combo := range repo.recipes[element] {
+			if _, ok := depths[combo.Item1]; !ok {
+				continue
+			}
+			if _, ok := depths[combo.Item2]; !ok {
+				continue
+			}
+			key := orderedCombination(combo.Item1, combo.Item2)
+			existing, ok := results[key]
+			if !ok {
+				combinations = append(combinations, key)
+			}
+			if len(existing) == 0 || existing[len(existing)-1] != element {
+				results[key] = append(existing, element)
+			}
+		}
+	}
+
+	tierNumbers := make([]int, 0, len(tiers))
+	for tier := range tiers {
+		tierNumbers = append(tierNumbers, tier)
+	}
+	sort.Ints(tierNumbers)
+
+	discovered := make(map[string]bool, len(depths))
+	for element, depth := range depths {
+		if depth == 0 {
+			discovered[element] = true
+		}
+	}
+
+	for _, tier := range tierNumbers {
+		// Every ingredient of these combinations is from a lower tier and already known
+		var candidates []Combination
+		for _, combo := range combinations {
+			if max(depths[combo.Item1], depths[combo.Item2]) == tier-1 {
+				candidates = append(candidates, combo)
+			}
+		}
+
+		checkpoint := TierCheckpoint{Tier: tier}
+		for remaining := len(tiers[tier]); remaining > 0; {
+			var best Combination
+			var bestNew []string
+			for _, combo := range candidates {
+				var fresh []string
+				for _, element := range results[combo] {
+					if !discovered[element] {
+						fresh = append(fresh, element)
+					}
+				}
+				if len(fresh) > len(bestNew) {
+					best, bestNew = combo, fresh
+				}
+			}
+
+			if len(bestNew) == 0 {
+				// MinDepths guarantees a recipe one tier up, so this only guards the loop
+				return nil, ErrNoRecipe
+			}
+			for _, element := range bestNew {
+				discovered[element] = true
+			}
+			remaining -= len(bestNew)
+			checkpoint.Steps++
+			checkpoint.Elements += len(bestNew)
+			plan.Steps = append(plan.Steps, FullPlanStep{Item1: best.Item1, Item2: best.Item2, Discovers: bestNew, Tier: tier})
+		}
+
+		checkpoint.TotalSteps = len(plan.Steps)
+		checkpoint.TotalElements = len(discovered)
+		plan.Checkpoints = append(plan.Checkpoints, checkpoint)
+	}
+
+	plan.Discovered = len(discovered)
+	return plan, nil
+}
@@ -0,0 +1,46 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanFullGame(t *testing.T) {
+	plan, err := PlanFullGame(newFixtureRepository())
+	if err != nil {
+		t.Fatalf("PlanFullGame error: %v", err)
+	}
+
+	// Every step combines elements already known and discovers something new
+	known := map[string]bool{"Air": true, "Earth": true, "Fire": true, "Water": true}
+	for i, step := range plan.Steps {
+		if !known[step.Item1] || !known[step.Item2] {
+			t.Fatalf("step %d combines %s + %s before both are discovered", i+1, step.Item1, step.Item2)
+		}
+		for _, element := range step.Discovers {
+			if known[element] {
+				t.Errorf("step %d discovers %s again", i+1, element)
+			}
+			known[element] = true
+		}
+	}
+	for _, element := range []string{"Mud", "Lava", "Steam", "Stone", "Brick"} {
+		if !known[element] {
+			t.Errorf("%s is never discovered", element)
+		}
+	}
+
+	if plan.Basic != 4 || plan.Discovered != 9 || len(plan.Steps) != 5 {
+		t.Errorf("basic = %d, discovered = %d, steps = %d, want 4, 9, 5", plan.Basic, plan.Discovered, len(plan.Steps))
+	}
+	if want := []string{"Ghost"}; !reflect.DeepEqual(plan.Unreachable, want) {
+		t.Errorf("unreachable = %q, want %q", plan.Unreachable, want)
+	}
+	want := []TierCheckpoint{
+		{Tier: 1, Steps: 3, TotalSteps: 3, Elements: 3, TotalElements: 7},
+		{Tier: 2, Steps: 2, TotalSteps: 5, Elements: 2, TotalElements: 9},
+	}
+	if !reflect.DeepEqual(plan.Checkpoints, want) {
+		t.Errorf("checkpoints = %+v, want %+v", plan.Checkpoints, want)
+	}
+}
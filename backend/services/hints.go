@@ -0,0 +1,252 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Weights of the hint score. Goal progress dominates so a chosen goal steers the
+// hints, unlocks rank the rest, and the tier only breaks near ties towards
+// simpler elements.
+const (
+	hintUnlockWeight = 1.0
+	hintGoalWeight   = 5.0
+	hintTierWeight   = 0.1
+)
+
+// Hint is a combination the player has not tried yet, scored by how useful it is
+type Hint struct {
+	Item1   string   `json:"item1"`
+	Item2   string   `json:"item2"`
+	Results []string `json:"results"`
+	Score   float64  `json:"score"`
+	// Unlocks counts the elements that become one combination away only thanks to the results
+	Unlocks int `json:"unlocks"`
+	// GoalSteps counts the combinations still needed for the goal after this one,
+	// or -1 without a reachable goal
+	GoalSteps int      `json:"goalSteps"`
+	Tier      int      `json:"tier"`
+	Reasons   []string `json:"reasons"`
+}
+
+// Hints suggests the next combinations for a player who has discovered the given
+// elements, best first. An empty discovered list means only the basic elements.
+// goal is optional; when set, hints that bring it closer score higher.
+func Hints(repo RecipeRepository, discovered []string, goal string, limit int) ([]Hint, error) {
+	known := make(map[string]bool)
+	if len(discovered) == 0 {
+		basicElements, err := repo.BasicElements()
+		if err != nil {
+			return nil, err
+		}
+		discovered = basicElements
+	}
+	for _, element := range discovered {
+		exists, err := repo.ElementExists(element)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%s: %w", element, ErrElementNotFound)
+		}
+		known[element] = true
+	}
+	if goal != "" {
+		exists, err := repo.ElementExists(goal)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%s: %w", goal, ErrElementNotFound)
+		}
+	}
+
+	tiers, err := MinDepths(repo)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := loadRecipeGraph(repo)
+	if err != nil {
+		return nil, err
+	}
+
+	// Every untried combination of two known elements that creates something new
+	var combinations []Combination
+	results := make(map[Combination][]string)
+	craftable := make(map[string]bool)
+	for element := range known {
+		products, err := repo.Products(element)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			if !known[product.Partner] || known[product.Result] {
+				continue
+			}
+			key := orderedCombination(element, product.Partner)
+			if _, ok := results[key]; !ok {
+				combinations = append(combinations, key)
+			}
+			if !contains(results[key], product.Result) {
+				results[key] = append(results[key], product.Result)
+			}
+			craftable[product.Result] = true
+		}
+	}
+
+	goalSteps := -1
+	if goal != "" && !known[goal] {
+		if steps, ok := graph.craftCost(known, goal); ok {
+			goalSteps = steps
+		}
+	}
+
+	hints := make([]Hint, 0, len(combinations))
+	for _, combo := range combinations {
+		hint := Hint{Item1: combo.Item1, Item2: combo.Item2, Results: results[combo], GoalSteps: -1}
+		sort.Strings(hint.Results)
+
+		after := make(map[string]bool, len(known)+len(hint.Results))
+		for element := range known {
+			after[element] = true
+		}
+		for _, result := range hint.Results {
+			after[result] = true
+		}
+
+		unlocked := make(map[string]bool)
+		hint.Tier = -1
+		for _, result := range hint.Results {
+			if tier, ok := tiers[result]; ok && (hint.Tier < 0 || tier < hint.Tier) {
+				hint.Tier = tier
+			}
+			products, err := repo.Products(result)
+			if err != nil {
+				return nil, err
+			}
+			for _, product := range products {
+				if after[product.Partner] && !after[product.Result] && !craftable[product.Result] {
+					unlocked[product.Result] = true
+				}
+			}
+		}
+		hint.Unlocks = len(unlocked)
+		hint.Score = hintUnlockWeight*float64(hint.Unlocks) - hintTierWeight*float64(max(hint.Tier, 0))
+		if hint.Unlocks > 0 {
+			hint.Reasons = append(hint.Reasons, fmt.Sprintf("unlocks %d new element(s) one combination away", hint.Unlocks))
+		}
+
+		if goalSteps >= 0 {
+			if after[goal] {
+				hint.GoalSteps = 0
+			} else if steps, ok := graph.craftCost(after, goal); ok {
+				hint.GoalSteps = steps
+			}
+			if hint.GoalSteps == 0 {
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("creates the goal %s", goal))
+			} else if progress := goalSteps - hint.GoalSteps; progress > 0 {
+				hint.Reasons = append(hint.Reasons, fmt.Sprintf("brings %s closer: %d combination(s) left instead of %d", goal, hint.GoalSteps, goalSteps))
+			}
+			hint.Score += hintGoalWeight * float64(goalSteps-hint.GoalSteps)
+		}
+		if hint.Tier >= 0 {
+			hint.Reasons = append(hint.Reasons, fmt.Sprintf("result is tier %d", hint.Tier))
+		}
+		hints = append(hints, hint)
+	}
+
+	sort.SliceStable(hints, func(i, j int) bool {
+		if hints[i].Score != hints[j].Score {
+			return hints[i].Score > hints[j].Score
+		}
+		if hints[i].Item1 != hints[j].Item1 {
+			return hints[i].Item1 < hints[j].Item1
+		}
+		return hints[i].Item2 < hints[j].Item2
+	})
+	if limit > 0 && len(hints) > limit {
+		hints = hints[:limit]
+	}
+	return hints, nil
+}
+
+// recipeGraph is every recipe of a dataset, read once so repeated cost queries
+// do not go back to the repository
+type recipeGraph struct {
+	elements []string
+	recipes  map[string][]Combination
+}
+
+func loadRecipeGraph(repo RecipeRepository) (*recipeGraph, error) {
+	elements, err := repo.Elements()
+	if err != nil {
+		return nil, err
+	}
+	graph := &recipeGraph{elements: elements, recipes: make(map[string][]Combination, len(elements))}
+	for _, element := range elements {
+		if graph.recipes[element], err = repo.Recipes(element); err != nil {
+			return nil, err
+		}
+	}
+	return graph, nil
+}
+
+// craftCost counts the combinations needed to craft goal from the known elements,
+// following the shallowest recipes like CraftingPlan. ok is false when no recipe
+// reaches the goal.
+func (g *recipeGraph) craftCost(known map[string]bool, goal string) (int, bool) {
+	depths := make(map[string]int, len(g.elements))
+	for element := range known {
+		depths[element] = 0
+	}
+	for changed := true; changed; {
+		changed = false
+		for _, element := range g.elements {
+			for _, combo := range g.recipes[element] {
+				depth1, ok1 := depths[combo.Item1]
+				depth2, ok2 := depths[combo.Item2]
+				if !ok1 || !ok2 {
+					continue
+				}
+				depth := 1 + max(depth1, depth2)
+				if current, ok := depths[element]; !ok || depth < current {
+					depths[element] = depth
+					changed = true
+				}
+			}
+		}
+	}
+	if _, ok := depths[goal]; !ok {
+		return 0, false
+	}
+
+	crafted := make(map[string]bool)
+	var craft func(element string)
+	craft = func(element string) {
+		if depths[element] == 0 || crafted[element] {
+			return
+		}
+		crafted[element] = true
+		for _, combo := range g.recipes[element] {
+			depth1, ok1 := depths[combo.Item1]
+			depth2, ok2 := depths[combo.Item2]
+			if ok1 && ok2 && 1+max(depth1, depth2) == depths[element] {
+				craft(combo.Item1)
+				craft(combo.Item2)
+				return
+			}
+		}
+	}
+	craft(goal)
+	return len(crafted), true
+}
+
+// contains reports whether list holds value
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
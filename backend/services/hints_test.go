@@ -0,0 +1,89 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestHints(t *testing.T) {
+	tests := []struct {
+		name       string
+		discovered []string
+		goal       string
+		limit      int
+		hints      []string // Combination of each hint, best first
+		unlocks    []int
+		goalSteps  []int
+	}{
+		{
+			name:      "basic elements",
+			limit:     10,
+			hints:     []string{"Earth + Fire", "Earth + Water", "Fire + Water"},
+			unlocks:   []int{1, 1, 0},
+			goalSteps: []int{-1, -1, -1},
+		},
+		{
+			name:      "goal steers the order",
+			goal:      "Brick",
+			limit:     2,
+			hints:     []string{"Earth + Water", "Earth + Fire"},
+			unlocks:   []int{1, 1},
+			goalSteps: []int{1, 2},
+		},
+		{
+			name:       "discovered results are skipped",
+			discovered: []string{"Air", "Earth", "Fire", "Water", "Mud"},
+			limit:      10,
+			hints:      []string{"Earth + Fire", "Fire + Water", "Fire + Mud"},
+			unlocks:    []int{1, 0, 0},
+			goalSteps:  []int{-1, -1, -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hints, err := Hints(newFixtureRepository(), tt.discovered, tt.goal, tt.limit)
+			if err != nil {
+				t.Fatalf("Hints error: %v", err)
+			}
+			var combinations []string
+			var unlocks, goalSteps []int
+			for _, hint := range hints {
+				combinations = append(combinations, hint.Item1+" + "+hint.Item2)
+				unlocks = append(unlocks, hint.Unlocks)
+				goalSteps = append(goalSteps, hint.GoalSteps)
+			}
+			if !reflect.DeepEqual(combinations, tt.hints) {
+				t.Fatalf("hints = %q, want %q", combinations, tt.hints)
+			}
+			if !reflect.DeepEqual(unlocks, tt.unlocks) || !reflect.DeepEqual(goalSteps, tt.goalSteps) {
+				t.Errorf("unlocks = %v, goal steps = %v, want %v, %v", unlocks, goalSteps, tt.unlocks, tt.goalSteps)
+			}
+			for i := 1; i < len(hints); i++ {
+				if hints[i].Score > hints[i-1].Score {
+					t.Errorf("hint %d scores %v above hint %d (%v)", i+1, hints[i].Score, i, hints[i-1].Score)
+				}
+			}
+		})
+	}
+}
+
+func TestHintsUnknownElement(t *testing.T) {
+	tests := []struct {
+		name       string
+		discovered []string
+		goal       string
+	}{
+		{name: "discovered", discovered: []string{"Fire", "Unicorn"}},
+		{name: "goal", goal: "Unicorn"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Hints(newFixtureRepository(), tt.discovered, tt.goal, 5); !errors.Is(err, ErrElementNotFound) {
+				t.Errorf("error = %v, want %v", err, ErrElementNotFound)
+			}
+		})
+	}
+}
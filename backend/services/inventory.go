@@ -0,0 +1,83 @@
+package services
+
+import "sort"
+
+// leaves returns the elements a search does not expand: the basic elements and
+// everything the player owns
+func (o SearchOptions) leaves(basicElements []string) []string {
+	owned := o.owned()
+	if len(owned) == 0 {
+		return basicElements
+	}
+	leaves := make([]string, 0, len(basicElements)+len(owned))
+	leaves = append(leaves, basicElements...)
+	for _, element := range owned {
+		if !isBasicElement(element, leaves) {
+			leaves = append(leaves, element)
+		}
+	}
+	return leaves
+}
+
+// owned returns the owned elements together with those of the player profile
+func (o SearchOptions) owned() []string {
+	if o.Profile == nil {
+		return o.Owned
+	}
+	return append(o.Profile.Elements(), o.Owned...)
+}
+
+// rankResults orders the recipe trees of an inventory search by the number of
+// combinations the player still has to make. recipes[i] is the recipe of
+// results[i]. Searches without owned elements keep the algorithm's order.
+func (s *Searcher) rankResults(results []*RecipeTree, recipes [][]RecipeStep, opts SearchOptions) []*RecipeTree {
+	owned := opts.ownedSet()
+	if owned == nil {
+		return results
+	}
+
+	for i, tree := range results {
+		markInventory(tree, recipes[i], owned)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].NewCombinations < results[j].NewCombinations
+	})
+	return results
+}
+
+// ownedSet returns the owned elements as a set, or nil when the player owns nothing
+func (o SearchOptions) ownedSet() map[string]bool {
+	elements := o.owned()
+	if len(elements) == 0 {
+		return nil
+	}
+	owned := make(map[string]bool, len(elements))
+	for _, element := range elements {
+		owned[element] = true
+	}
+	return owned
+}
+
+// markInventory fills in the owned flags and the number of new combinations of a
+// tree built from recipe
+func markInventory(tree *RecipeTree, recipe []RecipeStep, owned map[string]bool) {
+	tree.NewCombinations = countNewCombinations(recipe)
+	markOwned(tree.Children, owned)
+}
+
+// countNewCombinations counts the distinct elements a recipe crafts
+func countNewCombinations(recipe []RecipeStep) int {
+	crafted := make(map[string]bool, len(recipe))
+	for _, step := range recipe {
+		crafted[step.Result] = true
+	}
+	return len(crafted)
+}
+
+// markOwned flags the tree nodes the player already has
+func markOwned(nodes []*RecipeTree, owned map[string]bool) {
+	for _, node := range nodes {
+		node.Owned = owned[node.Name]
+		markOwned(node.Children, owned)
+	}
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSearchOwnedInventory(t *testing.T) {
+	searcher := newFixtureSearcher()
+	for _, algorithm := range Algorithms {
+		t.Run(algorithm, func(t *testing.T) {
+			result, err := searcher.Search(algorithm, "Brick", "All", 0, SearchOptions{Owned: []string{"Stone"}})
+			if err != nil {
+				t.Fatalf("Search error: %v", err)
+			}
+			if result.Status != StatusOK || len(result.Results) != 2 {
+				t.Fatalf("status = %s with %d recipes, want %s with 2", result.Status, len(result.Results), StatusOK)
+			}
+
+			// The owned Stone is a leaf, so its recipe is pruned and ranked first
+			first, second := result.Results[0], result.Results[1]
+			if want := []string{"Brick = Stone + Fire"}; !reflect.DeepEqual(first.Recipe, want) {
+				t.Errorf("first recipe = %q, want %q", first.Recipe, want)
+			}
+			if first.NewCombinations != 1 || second.NewCombinations != 2 {
+				t.Errorf("new combinations = %d, %d, want 1, 2", first.NewCombinations, second.NewCombinations)
+			}
+			if stone := first.Children[0]; stone.Name != "Stone" || !stone.Owned || len(stone.Children) != 0 {
+				t.Errorf("Stone node = %+v, want an owned leaf", stone)
+			}
+		})
+	}
+}
+
+func TestSearchOwnedElement(t *testing.T) {
+	result, err := newFixtureSearcher().Search("BFS", "Stone", "All", 0, SearchOptions{Owned: []string{"Stone"}})
+	if err != nil {
+		t.Fatalf("Search error: %v", err)
+	}
+	if result.Status != StatusBasicElement {
+		t.Errorf("status = %s, want %s", result.Status, StatusBasicElement)
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Suggestion is an element name close to what the user typed
+type Suggestion struct {
+	Name     string `json:"name"`
+	Image    string `json:"image"`
+	Prefix   bool   `json:"prefix"`   // The name starts with the query
+	Distance int    `json:"distance"` // Edit distance between the query and the name
+}
+
+// names builds the name index of the searcher's dataset on first use
+func (s *Searcher) names() (*nameIndex, error) {
+	s.indexOnce.Do(func() {
+		var elements []string
+		if elements, s.indexErr = s.repo.Elements(); s.indexErr == nil {
+			s.index = newNameIndex(elements)
+		}
+	})
+	return s.index, s.indexErr
+}
+
+// Resolve returns the dataset's spelling of an element name typed with any case,
+// accents or spacing, for example "acid  RAIN" for "Acid rain"
+func (s *Searcher) Resolve(name string) (string, bool, error) {
+	index, err := s.names()
+	if err != nil {
+		return "", false, err
+	}
+	element, ok := index.resolve(name)
+	return element, ok, nil
+}
+
+// Suggest returns up to limit element names matching a partial or misspelled query
+func (s *Searcher) Suggest(query string, limit int) ([]Suggestion, error) {
+	index, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+	suggestions := index.suggest(query, limit)
+	for i := range suggestions {
+		suggestions[i].Image = s.repo.ImageURL(suggestions[i].Name)
+	}
+	return suggestions, nil
+}
+
+// nameIndex resolves element names regardless of case, accents and spacing
+type nameIndex struct {
+	names      []string
+	normalized []string
+	exact      map[string]bool
+	byKey      map[string]string
+}
+
+func newNameIndex(elements []string) *nameIndex {
+	index := &nameIndex{
+		names:      elements,
+		normalized: make([]string, len(elements)),
+		exact:      make(map[string]bool, len(elements)),
+		byKey:      make(map[string]string, len(elements)),
+	}
+	for i, element := range elements {
+		index.exact[element] = true
+		key := normalizeName(element)
+		index.normalized[i] = key
+		if _, ok := index.byKey[key]; !ok {
+			index.byKey[key] = element
+		}
+	}
+	return index
+}
+
+// normalizeName folds a name for comparison: Unicode compatibility decomposition
+// without combining marks, lower case, single spaces
+func normalizeName(name string) string {
+	var b strings.Builder
+	space := false
+	for _, r := range norm.NFKD.String(name) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			continue
+		case unicode.IsSpace(r) || r == '_' || r == '-':
+			space = b.Len() > 0
+			continue
+		}
+		if space {
+			b.WriteByte(' ')
+			space = false
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// resolve returns the dataset's spelling of name
+func (x *nameIndex) resolve(name string) (string, bool) {
+	if x.exact[name] {
+		return name, true
+	}
+	element, ok := x.byKey[normalizeName(name)]
+	return element, ok
+}
+
+// suggest ranks element names against a query: prefix matches first, then by edit
+// distance. Names further than a third of the query length are left out unless
+// they start with the query.
+func (x *nameIndex) suggest(query string, limit int) []Suggestion {
+	key := normalizeName(query)
+	if key == "" {
+		return []Suggestion{}
+	}
+	maxDistance := max(1, len([]rune(key))/3)
+
+	suggestions := []Suggestion{}
+	for i, name := range x.normalized {
+		suggestion := Suggestion{
+			Name:     x.names[i],
+			Prefix:   strings.HasPrefix(name, key),
+			Distance: editDistance(key, name),
+		}
+		if !suggestion.Prefix && suggestion.Distance > maxDistance {
+			continue
+		}
+		suggestions = append(suggestions, suggestion)
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		a, b := suggestions[i], suggestions[j]
+		if a.Prefix != b.Prefix {
+			return a.Prefix
+		}
+		if a.Distance != b.Distance {
+			return a.Distance < b.Distance
+		}
+		return a.Name < b.Name
+	})
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// editDistance is the optimal string alignment distance between two strings, in
+// runes: insertions, deletions, substitutions and swaps of adjacent runes cost one
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	beforePrevious := make([]int, len(target)+1)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(source); i++ {
+		current[0] = i
+		for j := 1; j <= len(target); j++ {
+			cost := 1
+			if source[i-1] == target[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+			if i > 1 && j > 1 && source[i-1] == target[j-2] && source[i-2] == target[j-1] {
+				current[j] = min(current[j], beforePrevious[j-2]+1)
+			}
+		}
+		beforePrevious, previous, current = previous, current, beforePrevious
+	}
+	return previous[len(target)]
+}
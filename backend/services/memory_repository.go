@@ -0,0 +1,113 @@
+package services
+
+// ElementRow is one row of the elements table. Basic elements have empty items.
+type ElementRow struct {
+	Element string `json:"element"`
+	Item1   string `json:"item1"`
+	Item2   string `json:"item2"`
+}
+
+// MemoryRepository keeps the whole recipe graph in maps. It is used for datasets
+// loaded from files and for small synthetic graphs.
+type MemoryRepository struct {
+	elements map[string]bool
+	order    []string // Elements in the order they first appear
+	recipes  map[string][]Combination
+	products map[string][]Product
+	mapper   map[string]string
+}
+
+// NewMemoryRepository builds an in-memory repository from element rows and an image mapper
+func NewMemoryRepository(rows []ElementRow, mapper map[string]string) *MemoryRepository {
+	r := &MemoryRepository{
+		elements: make(map[string]bool),
+		recipes:  make(map[string][]Combination),
+		products: make(map[string][]Product),
+		mapper:   mapper,
+	}
+	if r.mapper == nil {
+		r.mapper = make(map[string]string)
+	}
+
+	for _, row := range rows {
+		if !r.elements[row.Element] {
+			r.elements[row.Element] = true
+			r.order = append(r.order, row.Element)
+		}
+		if row.Item1 == "" || row.Item2 == "" {
+			continue
+		}
+
+		r.recipes[row.Element] = append(r.recipes[row.Element], Combination{Item1: row.Item1, Item2: row.Item2})
+		r.products[row.Item1] = append(r.products[row.Item1], Product{Partner: row.Item2, Result: row.Element})
+		if row.Item1 != row.Item2 {
+			r.products[row.Item2] = append(r.products[row.Item2], Product{Partner: row.Item1, Result: row.Element})
+		}
+	}
+
+	return r
+}
+
+// ElementExists reports whether the element appeared in any row
+func (r *MemoryRepository) ElementExists(name string) (bool, error) {
+	return r.elements[name], nil
+}
+
+// Elements returns every element in the order it first appeared
+func (r *MemoryRepository) Elements() ([]string, error) {
+	return r.order, nil
+}
+
+// Recipes returns all direct combinations that create an element
+func (r *MemoryRepository) Recipes(element string) ([]Combination, error) {
+	return r.recipes[element], nil
+}
+
+// Products returns all elements created with the ingredient on either side of a recipe
+func (r *MemoryRepository) Products(ingredient string) ([]Product, error) {
+	return r.products[ingredient], nil
+}
+
+// BasicElements returns all elements without a complete recipe
+func (r *MemoryRepository) BasicElements() ([]string, error) {
+	basicElements := []string{}
+	for _, element := range r.order {
+		if len(r.recipes[element]) == 0 {
+			basicElements = append(basicElements, element)
+		}
+	}
+	return basicElements, nil
+}
+
+// ImageURL looks the element up in the image mapper
+func (r *MemoryRepository) ImageURL(element string) string {
+	return r.mapper[element]
+}
+
+// LoadMemoryRepository copies a whole dataset into memory, so repeated queries
+// (an interactive session, a planner) never touch the original source again
+func LoadMemoryRepository(repo RecipeRepository) (*MemoryRepository, error) {
+	elements, err := repo.Elements()
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []ElementRow
+	mapper := make(map[string]string, len(elements))
+	for _, element := range elements {
+		recipes, err := repo.Recipes(element)
+		if err != nil {
+			return nil, err
+		}
+		if len(recipes) == 0 {
+			rows = append(rows, ElementRow{Element: element})
+		}
+		for _, combo := range recipes {
+			rows = append(rows, ElementRow{Element: element, Item1: combo.Item1, Item2: combo.Item2})
+		}
+		if image := repo.ImageURL(element); image != "" {
+			mapper[element] = image
+		}
+	}
+	return NewMemoryRepository(rows, mapper), nil
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"errors"
+)
+
+// ErrNoPath is returned by ShortestPath when no chain of combinations links the elements
+var ErrNoPath = errors.New("no path between the elements")
+
+// ShortestPath finds the fewest combinations leading from one element to another,
+// where every step combines the previous result with some partner element.
+// For example Mud to Brick is the single step Brick = Mud + Fire.
+func ShortestPath(repo RecipeRepository, from, to string) ([]RecipeStep, error) {
+	for _, element := range []string{from, to} {
+		exists, err := repo.ElementExists(element)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, ErrElementNotFound
+		}
+	}
+	if from == to {
+		return []RecipeStep{}, nil
+	}
+
+	// Plain BFS over the ingredient -> product relation, remembering how each element was reached
+	reachedBy := map[string]RecipeStep{from: {}}
+	queue := []string{from}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		products, err := repo.Products(current)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			if _, seen := reachedBy[product.Result]; seen {
+				continue
+			}
+			reachedBy[product.Result] = RecipeStep{Result: product.Result, Item1: current, Item2: product.Partner}
+			if product.Result == to {
+				return walkBack(reachedBy, from, to), nil
+			}
+			queue = append(queue, product.Result)
+		}
+	}
+
+	return nil, ErrNoPath
+}
+
+// walkBack rebuilds the path found by ShortestPath, first step first
+func walkBack(reachedBy map[string]RecipeStep, from, to string) []RecipeStep {
+	var steps []RecipeStep
+	for element := to; element != from; element = reachedBy[element].Item1 {
+		steps = append(steps, reachedBy[element])
+	}
+	for i, j := 0, len(steps)-1; i < j; i, j = i+1, j-1 {
+		steps[i], steps[j] = steps[j], steps[i]
+	}
+	return steps
+}
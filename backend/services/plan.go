@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+)
+
+// Errors returned when an element cannot be crafted
+var (
+	ErrElementNotFound = errors.New("element not found")
+	ErrNoRecipe        = errors.New("no recipe reaches the element from the basic elements")
+)
+
+// CraftingPlan returns the combinations needed to craft an element starting from the
+// basic elements, ingredients first. It follows the shallowest recipe of every element
+// on the way and crafts each intermediate element only once. Basic elements need no steps.
+func CraftingPlan(repo RecipeRepository, element string) ([]RecipeStep, error) {
+	exists, err := repo.ElementExists(element)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrElementNotFound
+	}
+
+	depths, err := MinDepths(repo)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := depths[element]; !ok {
+		return nil, ErrNoRecipe
+	}
+
+	steps := []RecipeStep{}
+	crafted := make(map[string]bool)
+
+	var craft func(element string) error
+	craft = func(element string) error {
+		if depths[element] == 0 || crafted[element] {
+			return nil
+		}
+		crafted[element] = true
+
+		recipes, err := repo.Recipes(element)
+		if err != nil {
+			return err
+		}
+		for _, combo := range recipes {
+			depth1, ok1 := depths[combo.Item1]
+			depth2, ok2 := depths[combo.Item2]
+			if !ok1 || !ok2 || 1+max(depth1, depth2) != depths[element] {
+				continue
+			}
+			// Ingredients are strictly shallower, so the recursion always ends
+			if err := craft(combo.Item1); err != nil {
+				return err
+			}
+			if err := craft(combo.Item2); err != nil {
+				return err
+			}
+			steps = append(steps, RecipeStep{Result: element, Item1: combo.Item1, Item2: combo.Item2})
+			return nil
+		}
+		return ErrNoRecipe
+	}
+
+	if err := craft(element); err != nil {
+		return nil, err
+	}
+	return steps, nil
+}
@@ -0,0 +1,306 @@
+package services
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Errors returned by the player store
+var (
+	ErrPlayerNotFound = errors.New("player not found")
+	ErrNotDiscovered  = errors.New("element not discovered yet")
+)
+
+// defaultPlayersFile is the player database created next to alchemy.db
+const defaultPlayersFile = "players.db"
+
+// timeFormat stores UTC timestamps with a fixed width so they sort as text
+const timeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+
+// playerSchema creates the player tables. A discovery without a combination was
+// a basic element or imported from an existing save.
+const playerSchema = `
+CREATE TABLE IF NOT EXISTS players (
+	id         TEXT PRIMARY KEY,
+	dataset    TEXT NOT NULL,
+	created_at TEXT NOT NULL
+);
+CREATE TABLE IF NOT EXISTS discoveries (
+	player_id     TEXT NOT NULL,
+	element       TEXT NOT NULL,
+	discovered_at TEXT NOT NULL,
+	item1         TEXT,
+	item2         TEXT,
+	PRIMARY KEY (player_id, element)
+);`
+
+// Discovery is one element a player has found
+type Discovery struct {
+	Element      string    `json:"element"`
+	DiscoveredAt time.Time `json:"discoveredAt"`
+	Item1        string    `json:"item1,omitempty"` // Combination that produced the element
+	Item2        string    `json:"item2,omitempty"`
+}
+
+// Profile is the saved progress of one player on one dataset
+type Profile struct {
+	ID          string      `json:"id"`
+	Dataset     string      `json:"dataset"`
+	CreatedAt   time.Time   `json:"createdAt"`
+	Discoveries []Discovery `json:"discoveries"`
+}
+
+// Elements returns the names of every discovered element
+func (p *Profile) Elements() []string {
+	elements := make([]string, 0, len(p.Discoveries))
+	for _, discovery := range p.Discoveries {
+		elements = append(elements, discovery.Element)
+	}
+	return elements
+}
+
+// Has reports whether the player has discovered element
+func (p *Profile) Has(element string) bool {
+	for _, discovery := range p.Discoveries {
+		if discovery.Element == element {
+			return true
+		}
+	}
+	return false
+}
+
+// PlayerStore keeps player profiles in a SQLite database of its own, so alchemy.db
+// stays read-only
+type PlayerStore struct {
+	db *sql.DB
+}
+
+// NewPlayerStore creates the player tables in db when missing
+func NewPlayerStore(db *sql.DB) (*PlayerStore, error) {
+	if _, err := db.Exec(playerSchema); err != nil {
+		return nil, fmt.Errorf("gagal menyiapkan database pemain: %w", err)
+	}
+	return &PlayerStore{db: db}, nil
+}
+
+// OpenPlayerStore opens or creates the player database at path
+func OpenPlayerStore(path string) (*PlayerStore, error) {
+	db, err := openPlayerDatabase(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewPlayerStore(db)
+}
+
+// OpenDefaultPlayerStore opens the player database set in ALCHEMY_PLAYERS_PATH,
+// or players.db in the directory of the dataset database
+func OpenDefaultPlayerStore() (*PlayerStore, error) {
+	path := os.Getenv("ALCHEMY_PLAYERS_PATH")
+	if path == "" {
+		dataPath := os.Getenv("ALCHEMY_DB_PATH")
+		if dataPath == "" {
+			dataPath = defaultDatabasePath
+		}
+		path = filepath.Join(filepath.Dir(dataPath), defaultPlayersFile)
+	}
+	return OpenPlayerStore(path)
+}
+
+// Profile loads a player's profile, discoveries in the order they were made
+func (s *PlayerStore) Profile(id string) (*Profile, error) {
+	profile := &Profile{ID: id, Discoveries: []Discovery{}}
+	var createdAt string
+	err := s.db.QueryRow("SELECT dataset, created_at FROM players WHERE id = ?", id).Scan(&profile.Dataset, &createdAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrPlayerNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if profile.CreatedAt, err = time.Parse(timeFormat, createdAt); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.Query(`SELECT element, discovered_at, IFNULL(item1, ''), IFNULL(item2, '')
+		FROM discoveries WHERE player_id = ? ORDER BY discovered_at, rowid`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var discovery Discovery
+		var discoveredAt string
+		if err := rows.Scan(&discovery.Element, &discoveredAt, &discovery.Item1, &discovery.Item2); err != nil {
+			return nil, err
+		}
+		if discovery.DiscoveredAt, err = time.Parse(timeFormat, discoveredAt); err != nil {
+			return nil, err
+		}
+		profile.Discoveries = append(profile.Discoveries, discovery)
+	}
+	return profile, rows.Err()
+}
+
+// Import adds a list of discovered elements to a player's profile, for example from
+// an existing save. The profile is created on the dataset when missing and always
+// holds the basic elements. It returns the elements that were not discovered yet.
+func (s *PlayerStore) Import(repo RecipeRepository, id, dataset string, elements []string) ([]Discovery, error) {
+	basicElements, err := repo.BasicElements()
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range elements {
+		exists, err := repo.ElementExists(element)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, fmt.Errorf("%s: %w", element, ErrElementNotFound)
+		}
+	}
+
+	now := time.Now().UTC()
+	discoveries := make([]Discovery, 0, len(basicElements)+len(elements))
+	for _, element := range basicElements {
+		discoveries = append(discoveries, Discovery{Element: element, DiscoveredAt: now})
+	}
+	for _, element := range elements {
+		discoveries = append(discoveries, Discovery{Element: element, DiscoveredAt: now})
+	}
+	return s.record(id, dataset, now, discoveries)
+}
+
+// Craft combines two discovered elements of a player and records every element the
+// combination creates. It returns all results and the ones discovered just now.
+func (s *PlayerStore) Craft(repo RecipeRepository, id, item1, item2 string) ([]string, []Discovery, error) {
+	profile, err := s.Profile(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range []string{item1, item2} {
+		if !profile.Has(item) {
+			return nil, nil, fmt.Errorf("%s: %w", item, ErrNotDiscovered)
+		}
+	}
+
+	products, err := repo.Products(item1)
+	if err != nil {
+		return nil, nil, err
+	}
+	now := time.Now().UTC()
+	results := []string{}
+	var discoveries []Discovery
+	for _, product := range products {
+		if product.Partner != item2 || contains(results, product.Result) {
+			continue
+		}
+		results = append(results, product.Result)
+		discoveries = append(discoveries, Discovery{Element: product.Result, DiscoveredAt: now, Item1: item1, Item2: item2})
+	}
+
+	added, err := s.record(id, profile.Dataset, now, discoveries)
+	return results, added, err
+}
+
+// record creates the player when missing and inserts the discoveries the player
+// does not have yet, in one transaction. It returns the inserted discoveries.
+func (s *PlayerStore) record(id, dataset string, now time.Time, discoveries []Discovery) ([]Discovery, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("INSERT OR IGNORE INTO players (id, dataset, created_at) VALUES (?, ?, ?)",
+		id, dataset, now.Format(timeFormat)); err != nil {
+		return nil, err
+	}
+	insert, err := tx.Prepare(`INSERT OR IGNORE INTO discoveries (player_id, element, discovered_at, item1, item2)
+		VALUES (?, ?, ?, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+	defer insert.Close()
+
+	added := []Discovery{}
+	for _, discovery := range discoveries {
+		result, err := insert.Exec(id, discovery.Element, discovery.DiscoveredAt.Format(timeFormat),
+			nullable(discovery.Item1), nullable(discovery.Item2))
+		if err != nil {
+			return nil, err
+		}
+		if inserted, err := result.RowsAffected(); err == nil && inserted > 0 {
+			added = append(added, discovery)
+		}
+	}
+	return added, tx.Commit()
+}
+
+// nullable stores an empty combination item as NULL
+func nullable(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
+
+// TierProgress is the share of one tier's elements a player has discovered
+type TierProgress struct {
+	Tier       int     `json:"tier"`
+	Total      int     `json:"total"`
+	Discovered int     `json:"discovered"`
+	Percent    float64 `json:"percent"`
+}
+
+// Progress reports a profile's completion per tier of the dataset (see MinDepths)
+// and overall. Elements unreachable from the basic elements are not counted.
+func Progress(repo RecipeRepository, profile *Profile) ([]TierProgress, TierProgress, error) {
+	depths, err := MinDepths(repo)
+	if err != nil {
+		return nil, TierProgress{}, err
+	}
+
+	owned := make(map[string]bool, len(profile.Discoveries))
+	for _, discovery := range profile.Discoveries {
+		owned[discovery.Element] = true
+	}
+
+	tiers := make(map[int]*TierProgress)
+	overall := TierProgress{Tier: -1}
+	for element, depth := range depths {
+		tier, ok := tiers[depth]
+		if !ok {
+			tier = &TierProgress{Tier: depth}
+			tiers[depth] = tier
+		}
+		tier.Total++
+		overall.Total++
+		if owned[element] {
+			tier.Discovered++
+			overall.Discovered++
+		}
+	}
+
+	progress := make([]TierProgress, 0, len(tiers))
+	for _, tier := range tiers {
+		tier.Percent = percent(tier.Discovered, tier.Total)
+		progress = append(progress, *tier)
+	}
+	sort.Slice(progress, func(i, j int) bool { return progress[i].Tier < progress[j].Tier })
+	overall.Percent = percent(overall.Discovered, overall.Total)
+	return progress, overall, nil
+}
+
+// percent returns part/total as a percentage rounded to one decimal
+func percent(part, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
@@ -0,0 +1,141 @@
+//go:build cgo
+
+package services
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// newTestPlayerStore opens a player store in a temporary directory
+func newTestPlayerStore(t *testing.T) *PlayerStore {
+	t.Helper()
+	store, err := OpenPlayerStore(filepath.Join(t.TempDir(), "players.db"))
+	if err != nil {
+		t.Fatalf("OpenPlayerStore error: %v", err)
+	}
+	return store
+}
+
+// discoveredElements returns the elements of discoveries in order
+func discoveredElements(discoveries []Discovery) []string {
+	elements := []string{}
+	for _, discovery := range discoveries {
+		elements = append(elements, discovery.Element)
+	}
+	return elements
+}
+
+func TestPlayerImport(t *testing.T) {
+	repo, store := newFixtureRepository(), newTestPlayerStore(t)
+
+	if _, err := store.Profile("ana"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Fatalf("Profile error = %v, want %v", err, ErrPlayerNotFound)
+	}
+
+	added, err := store.Import(repo, "ana", "la2", []string{"Mud"})
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if want := []string{"Air", "Earth", "Fire", "Water", "Mud"}; !reflect.DeepEqual(discoveredElements(added), want) {
+		t.Errorf("first import added %q, want %q", discoveredElements(added), want)
+	}
+
+	// Importing again only adds what is new
+	added, err = store.Import(repo, "ana", "la2", []string{"Mud", "Lava"})
+	if err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	if want := []string{"Lava"}; !reflect.DeepEqual(discoveredElements(added), want) {
+		t.Errorf("second import added %q, want %q", discoveredElements(added), want)
+	}
+
+	// An unknown element rejects the whole import
+	if _, err := store.Import(repo, "ana", "la2", []string{"Stone", "Unicorn"}); !errors.Is(err, ErrElementNotFound) {
+		t.Errorf("Import error = %v, want %v", err, ErrElementNotFound)
+	}
+	profile, err := store.Profile("ana")
+	if err != nil {
+		t.Fatalf("Profile error: %v", err)
+	}
+	if profile.Dataset != "la2" || len(profile.Discoveries) != 6 || profile.Has("Stone") {
+		t.Errorf("profile = %s with %q, want la2 with 6 elements and no Stone", profile.Dataset, profile.Elements())
+	}
+}
+
+func TestPlayerCraft(t *testing.T) {
+	repo, store := newFixtureRepository(), newTestPlayerStore(t)
+	if _, err := store.Import(repo, "ana", "la2", []string{"Lava"}); err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		item1   string
+		item2   string
+		results []string
+		added   []string
+		err     error
+	}{
+		{name: "new element", item1: "Air", item2: "Lava", results: []string{"Stone"}, added: []string{"Stone"}},
+		{name: "known element", item1: "Lava", item2: "Air", results: []string{"Stone"}, added: []string{}},
+		{name: "no result", item1: "Air", item2: "Air", results: []string{}, added: []string{}},
+		{name: "not discovered", item1: "Mud", item2: "Fire", err: ErrNotDiscovered},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, added, err := store.Craft(repo, "ana", tt.item1, tt.item2)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Craft error = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(results, tt.results) || !reflect.DeepEqual(discoveredElements(added), tt.added) {
+				t.Errorf("Craft = %q, %q, want %q, %q", results, discoveredElements(added), tt.results, tt.added)
+			}
+		})
+	}
+
+	profile, err := store.Profile("ana")
+	if err != nil {
+		t.Fatalf("Profile error: %v", err)
+	}
+	stone := profile.Discoveries[len(profile.Discoveries)-1]
+	if stone.Element != "Stone" || stone.Item1 != "Air" || stone.Item2 != "Lava" {
+		t.Errorf("last discovery = %+v, want Stone from Air + Lava", stone)
+	}
+	if _, _, err := store.Craft(repo, "bob", "Air", "Fire"); !errors.Is(err, ErrPlayerNotFound) {
+		t.Errorf("Craft for a missing player error = %v, want %v", err, ErrPlayerNotFound)
+	}
+}
+
+func TestPlayerProgress(t *testing.T) {
+	repo, store := newFixtureRepository(), newTestPlayerStore(t)
+	if _, err := store.Import(repo, "ana", "la2", []string{"Mud", "Lava", "Stone"}); err != nil {
+		t.Fatalf("Import error: %v", err)
+	}
+	profile, err := store.Profile("ana")
+	if err != nil {
+		t.Fatalf("Profile error: %v", err)
+	}
+
+	tiers, overall, err := Progress(repo, profile)
+	if err != nil {
+		t.Fatalf("Progress error: %v", err)
+	}
+	want := []TierProgress{
+		{Tier: 0, Total: 4, Discovered: 4, Percent: 100},
+		{Tier: 1, Total: 3, Discovered: 2, Percent: 66.7},
+		{Tier: 2, Total: 2, Discovered: 1, Percent: 50},
+	}
+	if !reflect.DeepEqual(tiers, want) {
+		t.Errorf("tiers = %+v, want %+v", tiers, want)
+	}
+	if want := (TierProgress{Tier: -1, Total: 9, Discovered: 7, Percent: 77.8}); overall != want {
+		t.Errorf("overall = %+v, want %+v", overall, want)
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+// Combination is a single recipe: two ingredients that together create an element
+type Combination struct {
+	Item1 string
+	Item2 string
+}
+
+// Product is what an ingredient creates when it is combined with a partner
+type Product struct {
+	Partner string
+	Result  string
+}
+
+// RecipeRepository abstracts the source of the recipe graph so searches can run
+// against SQLite, an in-memory graph or an exported dataset file alike.
+type RecipeRepository interface {
+	// ElementExists reports whether the element is part of the dataset
+	ElementExists(name string) (bool, error)
+	// Elements returns every element of the dataset in dataset order
+	Elements() ([]string, error)
+	// Recipes returns every combination that creates the element
+	Recipes(element string) ([]Combination, error)
+	// Products returns every element the ingredient can create, with its partner
+	Products(ingredient string) ([]Product, error)
+	// BasicElements returns the elements that have no recipe (Air, Earth, Fire, Water)
+	BasicElements() ([]string, error)
+	// ImageURL returns the icon URL of the element, or "" if it is unknown
+	ImageURL(element string) string
+}
@@ -2,60 +2,163 @@
 package services
 
 import (
-	"database/sql"
-	"encoding/json"
-	"log"
-	"os"
+	"errors"
+	"sync"
 	"time"
-
-	_ "github.com/mattn/go-sqlite3"
 )
 
-var db *sql.DB
-var mapper map[string]string
+// ErrUnknownAlgorithm is returned by Search for an algorithm it does not implement
+var ErrUnknownAlgorithm = errors.New("unknown algorithm")
+
+// Algorithms lists the algorithm names accepted by Search
+var Algorithms = []string{"BFS", "DFS", "Bidirectional"}
+
+// SearchOptions narrows a search beyond the algorithm and the number of recipes
+type SearchOptions struct {
+	// Owned lists elements the player has already unlocked. They are treated as
+	// extra leaves next to the basic elements, so recipes stop expanding at them
+	// and results are ranked by the number of new combinations they need.
+	Owned []string
+	// Profile adds the discovered elements of a saved player to Owned
+	Profile *Profile
+	// Exclude lists elements, or combinations written as "Item1 + Item2", that
+	// must not appear anywhere in a recipe tree
+	Exclude []string
+	// Require lists elements that every recipe tree must contain
+	Require []string
+	// OnStep, when set, is called for every event of the traversal as it happens, for
+	// example to animate it. It runs on the search goroutine, so it may block to slow
+	// the search down or pause it; an error stops the search and is returned.
+	OnStep func(Step) error
+	// Trace, when set, records every event of the traversal together with the
+	// request and outcome of the search, see NewTrace
+	Trace *Trace
+}
+
+// Searcher runs the recipe searches against a recipe repository
+type Searcher struct {
+	repo RecipeRepository
+
+	indexOnce sync.Once
+	index     *nameIndex // Built on first use, see names
+	indexErr  error
+
+	versionOnce sync.Once
+	version     string // Computed on first use, see Version
+	versionErr  error
+}
 
-func init() {
-	var err error
-	db, err = sql.Open("sqlite3", "../database/alchemy.db")
+// NewSearcher creates a searcher that reads recipes from repo
+func NewSearcher(repo RecipeRepository) *Searcher {
+	return &Searcher{repo: repo}
+}
+
+// Repository returns the recipe repository the searcher reads from
+func (s *Searcher) Repository() RecipeRepository {
+	return s.repo
+}
+
+// Search runs the named algorithm and returns the recipe trees together with a
+// status telling why a search without recipes came back empty
+func (s *Searcher) Search(algorithm string, elementName string, recipeType string, maxRecipes int, opts SearchOptions) (*SearchResult, error) {
+	return s.search(algorithm, elementName, recipeType, maxRecipes, opts, nil)
+}
+
+// RecipeFunc receives a recipe tree as soon as a streaming search finds it. An
+// error stops the search, for example when the client has gone away.
+type RecipeFunc func(tree *RecipeTree) error
+
+// SearchStream runs a search like Search but hands every recipe tree to emit as
+// soon as it is found instead of collecting them, so "All" searches do not hold
+// every recipe in memory. Trees come in the order the algorithm finds them, not
+// ranked by new combinations. The result carries the status and counters; Results
+// is only set to the placeholder tree when the search found no recipe. An error
+// returned by emit stops the search and is returned as is.
+func (s *Searcher) SearchStream(algorithm string, elementName string, recipeType string, maxRecipes int, opts SearchOptions, emit RecipeFunc) (*SearchResult, error) {
+	return s.search(algorithm, elementName, recipeType, maxRecipes, opts, emit)
+}
+
+func (s *Searcher) search(algorithm string, elementName string, recipeType string, maxRecipes int, opts SearchOptions, emit RecipeFunc) (*SearchResult, error) {
+	// Accept names typed with any case, accents or spacing
+	if element, ok, err := s.Resolve(elementName); err == nil && ok {
+		elementName = element
+	}
+
+	var find recipeFinder
+	switch algorithm {
+	case "BFS":
+		find = s.findRecipesBFS
+	case "DFS":
+		find = s.findRecipesDFS
+	case "Bidirectional":
+		find = s.findRecipesBidirectional
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+
+	found := 0
+	counted := emit
+	if emit != nil {
+		counted = func(tree *RecipeTree) error {
+			found++
+			return emit(tree)
+		}
+	}
+	results, nodesVisited, executionTime, err := s.run(find, elementName, recipeType, maxRecipes, opts, counted)
 	if err != nil {
-		log.Fatalf("Gagal membuka database: %v", err)
-	} else {
-		log.Printf("Database ditemukan")
+		return nil, err
+	}
+	if emit == nil && HasRecipe(results) {
+		found = len(results)
 	}
 
-	file, err := os.Open("../database/mapper2.json")
-	if err != nil {
-		log.Fatalf("Gagal membuka mapper.json: %v", err)
+	status, message := s.classify(elementName, recipeType, maxRecipes, opts, found)
+	if found == 0 && len(results) > 0 {
+		// The placeholder tree says which of the empty outcomes this is
+		results[0].Recipe = []string{message}
 	}
-	defer file.Close()
-	if err := json.NewDecoder(file).Decode(&mapper); err != nil {
-		log.Fatalf("Gagal mendekode mapper.json: %v", err)
+	if opts.Trace != nil {
+		opts.Trace.finish(algorithm, elementName, recipeType, maxRecipes, status, nodesVisited, found)
 	}
+	return &SearchResult{
+		Status:        status,
+		Message:       message,
+		Results:       results,
+		NodesVisited:  nodesVisited,
+		ExecutionTime: executionTime,
+	}, nil
+}
+
+// HasRecipe reports whether search results hold actual recipes rather than the
+// placeholder returned for basic, unknown or unreachable elements
+func HasRecipe(results []*RecipeTree) bool {
+	return len(results) > 0 && len(results[0].Children) > 0
 }
 
-type Node struct {
-	Name     string
-	Children []*Node
+// RecipeTree is a recipe rendered as a tree of ingredients. Only the root carries
+// the list of recipe steps.
+type RecipeTree struct {
+	Name     string        `json:"name"`
+	Image    string        `json:"image"`
+	Children []*RecipeTree `json:"children"`
+	Recipe   []string      `json:"recipe,omitempty"`
+	// Only set for searches with owned elements
+	Owned           bool `json:"owned,omitempty"`
+	NewCombinations int  `json:"newCombinations,omitempty"`
 }
 
+// RecipeStep is one combination: Item1 + Item2 creates Result
 type RecipeStep struct {
-	Result string
-	Item1  string
-	Item2  string
+	Result string `json:"result"`
+	Item1  string `json:"item1"`
+	Item2  string `json:"item2"`
 }
 
 // Get all basic elements (Water, Fire, Earth, Air, etc.)
-func getBasicElements() []string {
-	basicElements := []string{}
-	rows, err := db.Query("SELECT DISTINCT element FROM elements WHERE element NOT IN (SELECT DISTINCT element FROM elements WHERE item1 IS NOT NULL AND item2 IS NOT NULL)")
-	if err == nil {
-		defer rows.Close()
-		for rows.Next() {
-			var element string
-			if err := rows.Scan(&element); err == nil {
-				basicElements = append(basicElements, element)
-			}
-		}
+func (s *Searcher) getBasicElements() []string {
+	basicElements, err := s.repo.BasicElements()
+	if err != nil {
+		return []string{}
 	}
 	return basicElements
 }
@@ -70,43 +173,14 @@ func isBasicElement(element string, basicElements []string) bool {
 	return false
 }
 
-// Helper function to get all direct combinations that create an element
-func getDirectCombinations(elementName string) ([]struct {
-	Item1, Item2 string
-}, error) {
-	var combinations []struct {
-		Item1, Item2 string
-	}
-
-	rows, err := db.Query("SELECT item1, item2 FROM elements WHERE element = ? AND item1 IS NOT NULL AND item2 IS NOT NULL", elementName)
-	if err != nil {
-		return combinations, err
-	}
-	defer rows.Close()
-
-	for rows.Next() {
-		var item1, item2 string
-		if err := rows.Scan(&item1, &item2); err != nil {
-			continue
-		}
-		combinations = append(combinations, struct {
-			Item1, Item2 string
-		}{Item1: item1, Item2: item2})
-	}
-
-	return combinations, nil
-}
-
 // Helper function for default result when no recipe is found
-func getDefaultResult(elementName string) []interface{} {
-	return []interface{}{
-		map[string]interface{}{
-			"name":     elementName,
-			"image":    mapper[elementName],
-			"children": []interface{}{},
-			"recipe":   []string{"This is a basic element or no recipe found"},
-		},
-	}
+func (s *Searcher) getDefaultResult(elementName string) []*RecipeTree {
+	return []*RecipeTree{{
+		Name:     elementName,
+		Image:    s.repo.ImageURL(elementName),
+		Children: []*RecipeTree{},
+		Recipe:   []string{"This is a basic element or no recipe found"},
+	}}
 }
 
 // Format recipe steps for display
@@ -122,28 +196,82 @@ func formatRecipeSteps(steps []RecipeStep) []string {
 	return formattedSteps
 }
 
-//================================================
-// BFS IMPLEMENTATION
-//================================================
+// recipeFinder is one of the findRecipes* traversals. It hands every complete recipe
+// to sink and returns the number of nodes it visited.
+type recipeFinder func(elementName string, basicElements []string, sink *recipeSink, rules *constraints) int
+
+// recipeSink receives the recipes a traversal finds. It collects them, or turns each
+// one into a tree for emit when the search streams, and tells the traversal when to stop.
+// Traversal events go through report.
+type recipeSink struct {
+	max     int // Stop after this many recipes
+	found   int
+	recipes [][]RecipeStep // Only filled without emit
+	emit    func(recipe []RecipeStep) error
+	onStep  func(Step) error
+	err     error // First error returned by emit, onStep or the repository
+}
 
-// BFS for recipe search
-func BFS(elementName string, recipeType string, maxRecipes int) ([]interface{}, int, float64) {
+// add records a complete recipe found after visiting nodesVisited nodes
+func (r *recipeSink) add(recipe []RecipeStep, nodesVisited int) {
+	r.found++
+	if r.onStep != nil {
+		r.report(Step{Kind: StepRecipe, Element: recipe[0].Result, NodesVisited: nodesVisited, Recipe: formatRecipeSteps(recipe)})
+		if r.err != nil {
+			return
+		}
+	}
+	if r.emit == nil {
+		r.recipes = append(r.recipes, recipe)
+		return
+	}
+	r.err = r.emit(recipe)
+}
+
+// fail stops the traversal with an error from the repository
+func (r *recipeSink) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// full reports whether the traversal should stop
+func (r *recipeSink) full() bool {
+	return r.found >= r.max || r.err != nil
+}
+
+// run checks the target, runs find and turns the recipes into trees. With emit every
+// tree is handed over as soon as it is found, and only the placeholder of a search
+// without recipes is returned.
+func (s *Searcher) run(find recipeFinder, elementName string, recipeType string, maxRecipes int, opts SearchOptions, emit RecipeFunc) ([]*RecipeTree, int, float64, error) {
 	start := time.Now()
 	nodesVisited := 0
 
 	// Check if element exists in database
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM elements WHERE element = ?)", elementName).Scan(&exists)
-	if err != nil || !exists {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
+	exists, err := s.repo.ElementExists(elementName)
+	if err != nil {
+		return nil, nodesVisited, float64(time.Since(start).Milliseconds()), err
+	}
+	if !exists {
+		return s.getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds()), nil
 	}
 
-	// Get all basic elements
-	basicElements := getBasicElements()
-	
+	// Get all basic elements, plus the elements the player already owns
+	basicElements, err := s.repo.BasicElements()
+	if err != nil {
+		return nil, nodesVisited, float64(time.Since(start).Milliseconds()), err
+	}
+	basicElements = opts.leaves(basicElements)
+
 	// Check if this is already a basic element
 	if isBasicElement(elementName, basicElements) {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
+		return s.getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds()), nil
+	}
+
+	// An excluded target can never be part of a recipe tree
+	rules := newConstraints(opts)
+	if rules.excludes(elementName) {
+		return s.getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds()), nil
 	}
 
 	// Determine the number of recipes to find based on recipeType
@@ -157,29 +285,56 @@ func BFS(elementName string, recipeType string, maxRecipes int) ([]interface{},
 		desiredRecipeCount = 1000000
 	}
 
+	// Streamed trees are marked like ranked ones, only their order differs
+	sink := &recipeSink{max: desiredRecipeCount, onStep: opts.onStep()}
+	if emit != nil {
+		owned := opts.ownedSet()
+		sink.emit = func(recipe []RecipeStep) error {
+			tree := s.createRecipeTree(elementName, recipe)
+			if owned != nil {
+				markInventory(tree, recipe, owned)
+			}
+			return emit(tree)
+		}
+	}
+
 	// Find recipes with early stopping
-	allRecipes, nodesVisitedCount := findRecipesBFS(elementName, basicElements, desiredRecipeCount)
-	nodesVisited = nodesVisitedCount
-	
+	nodesVisited = find(elementName, basicElements, sink, rules)
+	if sink.err != nil {
+		return nil, nodesVisited, float64(time.Since(start).Milliseconds()), sink.err
+	}
+
 	// If no recipes found, return default
-	if len(allRecipes) == 0 {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
+	if sink.found == 0 {
+		return s.getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds()), nil
 	}
-	
+	if emit != nil {
+		return nil, nodesVisited, float64(time.Since(start).Milliseconds()), nil
+	}
+
 	// Convert recipes to result format
-	var results []interface{}
-	for _, recipe := range allRecipes {
+	var results []*RecipeTree
+	for _, recipe := range sink.recipes {
 		// Create tree representation
-		treeRoot := createRecipeTree(elementName, recipe)
+		treeRoot := s.createRecipeTree(elementName, recipe)
 		results = append(results, treeRoot)
 	}
 
-	return results, nodesVisited, float64(time.Since(start).Milliseconds())
+	return s.rankResults(results, sink.recipes, opts), nodesVisited, float64(time.Since(start).Milliseconds()), nil
+}
+
+//================================================
+// BFS IMPLEMENTATION
+//================================================
+
+// BFS for recipe search
+func (s *Searcher) BFS(elementName string, recipeType string, maxRecipes int, opts SearchOptions) ([]*RecipeTree, int, float64) {
+	results, nodesVisited, executionTime, _ := s.run(s.findRecipesBFS, elementName, recipeType, maxRecipes, opts, nil)
+	return results, nodesVisited, executionTime
 }
 
 // Function to find recipes for an element using BFS with early stopping
-func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind int) ([][]RecipeStep, int) {
-	var allRecipes [][]RecipeStep
+func (s *Searcher) findRecipesBFS(elementName string, basicElements []string, sink *recipeSink, rules *constraints) int {
 	nodesVisited := 0
 	
 	// Queue for BFS
@@ -199,13 +354,18 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 	// Keep track of combinations we've added
 	processedCombinations := make(map[string]bool)
 	
-	for len(queue) > 0 && len(allRecipes) < maxRecipesToFind {
+	// The target is the first node on the queue
+	sink.report(Step{Kind: StepPush, Element: elementName})
+	
+	for len(queue) > 0 && !sink.full() {
 		current := queue[0]
 		queue = queue[1:]
 		nodesVisited++
+		sink.report(Step{Kind: StepVisit, Element: current.Element, Depth: len(current.Path), NodesVisited: nodesVisited})
 		
 		// Skip if we've already explored this element in the current path to avoid cycles
 		if current.Explored[current.Element] {
+			sink.report(Step{Kind: StepCycle, Element: current.Element, Depth: len(current.Path), NodesVisited: nodesVisited})
 			continue
 		}
 		
@@ -238,12 +398,15 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 			}
 			
 			// Only add if we haven't processed this exact recipe before
-			if !processedCombinations[recipeKey] {
+			// and it uses every required element
+			if processedCombinations[recipeKey] {
+				sink.dedup(current.Path, nodesVisited)
+			} else if rules.satisfied(current.Path) {
 				processedCombinations[recipeKey] = true
-				allRecipes = append(allRecipes, current.Path)
+				sink.add(current.Path, nodesVisited)
 				
 				// Check if we've found enough recipes
-				if len(allRecipes) >= maxRecipesToFind {
+				if sink.full() {
 					break
 				}
 			}
@@ -251,8 +414,12 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 		}
 		
 		// Get all combinations for this element
-		combinations, err := getDirectCombinations(current.Element)
-		if err != nil || len(combinations) == 0 {
+		combinations, err := s.repo.Recipes(current.Element)
+		if err != nil {
+			sink.fail(err)
+			break
+		}
+		if len(combinations) == 0 {
 			// If there are no combinations (basic element or missing), and this is the target element
 			if current.Element == elementName {
 				// Try next element in queue
@@ -265,6 +432,12 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 		
 		// Process each combination
 		for _, combo := range combinations {
+			// Prune combinations that use an excluded element or combination
+			if !rules.allows(combo) {
+				continue
+			}
+			sink.report(Step{Kind: StepExpand, Element: current.Element, Item1: combo.Item1, Item2: combo.Item2, Depth: len(current.Path), NodesVisited: nodesVisited})
+
 			// Create new step
 			newStep := RecipeStep{
 				Result: current.Element,
@@ -284,6 +457,7 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 					Path:    newPath,
 					Explored: explored,
 				})
+				sink.report(Step{Kind: StepPush, Element: combo.Item1, Depth: len(newPath), NodesVisited: nodesVisited})
 			}
 			
 			if !isBasicElement(combo.Item2, basicElements) {
@@ -292,6 +466,7 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 					Path:    newPath,
 					Explored: explored,
 				})
+				sink.report(Step{Kind: StepPush, Element: combo.Item2, Depth: len(newPath), NodesVisited: nodesVisited})
 			}
 			
 			// If both ingredients are basic elements, check if we have a complete path
@@ -303,12 +478,15 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 				}
 				
 				// Only add if we haven't processed this exact recipe before
-				if !processedCombinations[recipeKey] {
+				// and it uses every required element
+				if processedCombinations[recipeKey] {
+					sink.dedup(newPath, nodesVisited)
+				} else if rules.satisfied(newPath) {
 					processedCombinations[recipeKey] = true
-					allRecipes = append(allRecipes, newPath)
+					sink.add(newPath, nodesVisited)
 					
 					// Check if we've found enough recipes
-					if len(allRecipes) >= maxRecipesToFind {
+					if sink.full() {
 						break
 					}
 				}
@@ -316,21 +494,21 @@ func findRecipesBFS(elementName string, basicElements []string, maxRecipesToFind
 		}
 	}
 	
-	return allRecipes, nodesVisited
+	return nodesVisited
 }
 
 // Create a tree representation for a recipe
-func createRecipeTree(elementName string, recipe []RecipeStep) map[string]interface{} {
-	return map[string]interface{}{
-		"name":     elementName,
-		"image":    mapper[elementName],
-		"children": buildElementTree(elementName, recipe),
-		"recipe":   formatRecipeSteps(recipe),
+func (s *Searcher) createRecipeTree(elementName string, recipe []RecipeStep) *RecipeTree {
+	return &RecipeTree{
+		Name:     elementName,
+		Image:    s.repo.ImageURL(elementName),
+		Children: s.buildElementTree(elementName, recipe),
+		Recipe:   formatRecipeSteps(recipe),
 	}
 }
 
 // Build tree for an element recursively
-func buildElementTree(elementName string, recipe []RecipeStep) []map[string]interface{} {
+func (s *Searcher) buildElementTree(elementName string, recipe []RecipeStep) []*RecipeTree {
 	// Find the step for this element
 	var stepForElement *RecipeStep
 	for i, step := range recipe {
@@ -342,25 +520,25 @@ func buildElementTree(elementName string, recipe []RecipeStep) []map[string]inte
 	
 	// If not found, this is a basic element
 	if stepForElement == nil {
-		return []map[string]interface{}{}
+		return []*RecipeTree{}
 	}
 	
 	// Create nodes for ingredients
-	item1Node := map[string]interface{}{
-		"name":  stepForElement.Item1,
-		"image": mapper[stepForElement.Item1],
+	item1Node := &RecipeTree{
+		Name:  stepForElement.Item1,
+		Image: s.repo.ImageURL(stepForElement.Item1),
 	}
 	
-	item2Node := map[string]interface{}{
-		"name":  stepForElement.Item2,
-		"image": mapper[stepForElement.Item2],
+	item2Node := &RecipeTree{
+		Name:  stepForElement.Item2,
+		Image: s.repo.ImageURL(stepForElement.Item2),
 	}
 	
 	// Recursively build trees for ingredients
-	item1Node["children"] = buildElementTree(stepForElement.Item1, recipe)
-	item2Node["children"] = buildElementTree(stepForElement.Item2, recipe)
+	item1Node.Children = s.buildElementTree(stepForElement.Item1, recipe)
+	item2Node.Children = s.buildElementTree(stepForElement.Item2, recipe)
 	
-	return []map[string]interface{}{item1Node, item2Node}
+	return []*RecipeTree{item1Node, item2Node}
 }
 
 //================================================
@@ -368,60 +546,13 @@ func buildElementTree(elementName string, recipe []RecipeStep) []map[string]inte
 //================================================
 
 // DFS for recipe search
-func DFS(elementName string, recipeType string, maxRecipes int) ([]interface{}, int, float64) {
-	start := time.Now()
-	nodesVisited := 0
-
-	// Check if element exists in database
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM elements WHERE element = ?)", elementName).Scan(&exists)
-	if err != nil || !exists {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
-	}
-
-	// Get all basic elements
-	basicElements := getBasicElements()
-	
-	// Check if this is already a basic element
-	if isBasicElement(elementName, basicElements) {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
-	}
-
-	// Determine the number of recipes to find based on recipeType
-	var desiredRecipeCount int
-	if recipeType == "One" {
-		desiredRecipeCount = 1
-	} else if recipeType == "Limit" {
-		desiredRecipeCount = maxRecipes
-	} else {
-		// For "All", set to a very large number to find all recipes
-		desiredRecipeCount = 1000000
-	}
-
-	// Find recipes with early stopping
-	allRecipes, nodesVisitedCount := findRecipesDFS(elementName, basicElements, desiredRecipeCount)
-	nodesVisited = nodesVisitedCount
-	
-	// If no recipes found, return default
-	if len(allRecipes) == 0 {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
-	}
-	
-	// Convert recipes to result format
-	var results []interface{}
-	for _, recipe := range allRecipes {
-		// Create tree representation
-		treeRoot := createRecipeTree(elementName, recipe)
-		
-		results = append(results, treeRoot)
-	}
-
-	return results, nodesVisited, float64(time.Since(start).Milliseconds())
+func (s *Searcher) DFS(elementName string, recipeType string, maxRecipes int, opts SearchOptions) ([]*RecipeTree, int, float64) {
+	results, nodesVisited, executionTime, _ := s.run(s.findRecipesDFS, elementName, recipeType, maxRecipes, opts, nil)
+	return results, nodesVisited, executionTime
 }
 
 // Function to find recipes for an element using DFS with early stopping
-func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind int) ([][]RecipeStep, int) {
-	var allRecipes [][]RecipeStep
+func (s *Searcher) findRecipesDFS(elementName string, basicElements []string, sink *recipeSink, rules *constraints) int {
 	nodesVisited := 0
 	
 	// Stack for DFS
@@ -441,15 +572,20 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 	// Keep track of combinations we've added
 	processedCombinations := make(map[string]bool)
 	
-	for len(stack) > 0 && len(allRecipes) < maxRecipesToFind {
+	// The target is the first node on the stack
+	sink.report(Step{Kind: StepPush, Element: elementName})
+	
+	for len(stack) > 0 && !sink.full() {
 		// Pop from stack (last in, first out)
 		last := len(stack) - 1
 		current := stack[last]
 		stack = stack[:last]
 		nodesVisited++
+		sink.report(Step{Kind: StepVisit, Element: current.Element, Depth: len(current.Path), NodesVisited: nodesVisited})
 		
 		// Skip if we've already explored this element in the current path to avoid cycles
 		if current.Explored[current.Element] {
+			sink.report(Step{Kind: StepCycle, Element: current.Element, Depth: len(current.Path), NodesVisited: nodesVisited})
 			continue
 		}
 		
@@ -482,12 +618,15 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 			}
 			
 			// Only add if we haven't processed this exact recipe before
-			if !processedCombinations[recipeKey] {
+			// and it uses every required element
+			if processedCombinations[recipeKey] {
+				sink.dedup(current.Path, nodesVisited)
+			} else if rules.satisfied(current.Path) {
 				processedCombinations[recipeKey] = true
-				allRecipes = append(allRecipes, current.Path)
+				sink.add(current.Path, nodesVisited)
 				
 				// Check if we've found enough recipes
-				if len(allRecipes) >= maxRecipesToFind {
+				if sink.full() {
 					break
 				}
 			}
@@ -495,8 +634,12 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 		}
 		
 		// Get all combinations for this element
-		combinations, err := getDirectCombinations(current.Element)
-		if err != nil || len(combinations) == 0 {
+		combinations, err := s.repo.Recipes(current.Element)
+		if err != nil {
+			sink.fail(err)
+			break
+		}
+		if len(combinations) == 0 {
 			// If there are no combinations (basic element or missing), and this is the target element
 			if current.Element == elementName {
 				// Try next element in stack
@@ -510,6 +653,12 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 		// Process each combination
 		for i := len(combinations) - 1; i >= 0; i-- { // Reverse order for DFS
 			combo := combinations[i]
+
+			// Prune combinations that use an excluded element or combination
+			if !rules.allows(combo) {
+				continue
+			}
+			sink.report(Step{Kind: StepExpand, Element: current.Element, Item1: combo.Item1, Item2: combo.Item2, Depth: len(current.Path), NodesVisited: nodesVisited})
 			
 			// Create new step
 			newStep := RecipeStep{
@@ -530,6 +679,7 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 					Path:    newPath,
 					Explored: explored,
 				})
+				sink.report(Step{Kind: StepPush, Element: combo.Item2, Depth: len(newPath), NodesVisited: nodesVisited})
 			}
 			
 			if !isBasicElement(combo.Item1, basicElements) {
@@ -538,6 +688,7 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 					Path:    newPath,
 					Explored: explored,
 				})
+				sink.report(Step{Kind: StepPush, Element: combo.Item1, Depth: len(newPath), NodesVisited: nodesVisited})
 			}
 			
 			// If both ingredients are basic elements, check if we have a complete path
@@ -549,12 +700,15 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 				}
 				
 				// Only add if we haven't processed this exact recipe before
-				if !processedCombinations[recipeKey] {
+				// and it uses every required element
+				if processedCombinations[recipeKey] {
+					sink.dedup(newPath, nodesVisited)
+				} else if rules.satisfied(newPath) {
 					processedCombinations[recipeKey] = true
-					allRecipes = append(allRecipes, newPath)
+					sink.add(newPath, nodesVisited)
 					
 					// Check if we've found enough recipes
-					if len(allRecipes) >= maxRecipesToFind {
+					if sink.full() {
 						break
 					}
 				}
@@ -562,7 +716,7 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 		}
 	}
 	
-	return allRecipes, nodesVisited
+	return nodesVisited
 }
 
 //================================================
@@ -570,60 +724,13 @@ func findRecipesDFS(elementName string, basicElements []string, maxRecipesToFind
 //================================================
 
 // Bidirectional search for recipes
-func Bidirectional(elementName string, recipeType string, maxRecipes int) ([]interface{}, int, float64) {
-	start := time.Now()
-	nodesVisited := 0
-
-	// Check if element exists in database
-	var exists bool
-	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM elements WHERE element = ?)", elementName).Scan(&exists)
-	if err != nil || !exists {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
-	}
-
-	// Get all basic elements
-	basicElements := getBasicElements()
-	
-	// Check if this is already a basic element
-	if isBasicElement(elementName, basicElements) {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
-	}
-
-	// Determine the number of recipes to find based on recipeType
-	var desiredRecipeCount int
-	if recipeType == "One" {
-		desiredRecipeCount = 1
-	} else if recipeType == "Limit" {
-		desiredRecipeCount = maxRecipes
-	} else {
-		// For "All", set to a very large number to find all recipes
-		desiredRecipeCount = 1000000
-	}
-
-	// Find recipes with early stopping
-	allRecipes, nodesVisitedCount := findRecipesBidirectional(elementName, basicElements, desiredRecipeCount)
-	nodesVisited = nodesVisitedCount
-	
-	// If no recipes found, return default
-	if len(allRecipes) == 0 {
-		return getDefaultResult(elementName), nodesVisited, float64(time.Since(start).Milliseconds())
-	}
-	
-	// Convert recipes to result format
-	var results []interface{}
-	for _, recipe := range allRecipes {
-		// Create tree representation
-		treeRoot := createRecipeTree(elementName, recipe)
-		
-		results = append(results, treeRoot)
-	}
-
-	return results, nodesVisited, float64(time.Since(start).Milliseconds())
+func (s *Searcher) Bidirectional(elementName string, recipeType string, maxRecipes int, opts SearchOptions) ([]*RecipeTree, int, float64) {
+	results, nodesVisited, executionTime, _ := s.run(s.findRecipesBidirectional, elementName, recipeType, maxRecipes, opts, nil)
+	return results, nodesVisited, executionTime
 }
 
 // Function to find recipes using bidirectional search with early stopping
-func findRecipesBidirectional(elementName string, basicElements []string, maxRecipesToFind int) ([][]RecipeStep, int) {
-	var allRecipes [][]RecipeStep
+func (s *Searcher) findRecipesBidirectional(elementName string, basicElements []string, sink *recipeSink, rules *constraints) int {
 	nodesVisited := 0
 	
 	// Keep track of combinations we've added
@@ -660,14 +767,19 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 		})
 	}
 	
+	// The target is the first node on the forward queue
+	sink.report(Step{Kind: StepPush, Element: elementName})
+	
 	// Process forward queue first to find direct paths
-	for len(forwardQueue) > 0 && len(allRecipes) < maxRecipesToFind {
+	for len(forwardQueue) > 0 && !sink.full() {
 		current := forwardQueue[0]
 		forwardQueue = forwardQueue[1:]
 		nodesVisited++
+		sink.report(Step{Kind: StepVisit, Element: current.Element, Depth: len(current.Path), NodesVisited: nodesVisited})
 		
 		// Skip if we've already explored this element in the current path
 		if current.Explored[current.Element] {
+			sink.report(Step{Kind: StepCycle, Element: current.Element, Depth: len(current.Path), NodesVisited: nodesVisited})
 			continue
 		}
 		
@@ -696,12 +808,15 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 			}
 			
 			// Only add if we haven't processed this exact recipe before
-			if !processedCombinations[recipeKey] {
+			// and it uses every required element
+			if processedCombinations[recipeKey] {
+				sink.dedup(current.Path, nodesVisited)
+			} else if rules.satisfied(current.Path) {
 				processedCombinations[recipeKey] = true
-				allRecipes = append(allRecipes, current.Path)
+				sink.add(current.Path, nodesVisited)
 				
 				// Check if we've found enough recipes
-				if len(allRecipes) >= maxRecipesToFind {
+				if sink.full() {
 					break
 				}
 			}
@@ -709,13 +824,23 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 		}
 		
 		// Get all combinations for this element
-		combinations, err := getDirectCombinations(current.Element)
-		if err != nil || len(combinations) == 0 {
+		combinations, err := s.repo.Recipes(current.Element)
+		if err != nil {
+			sink.fail(err)
+			break
+		}
+		if len(combinations) == 0 {
 			continue
 		}
 		
 		// Process each combination
 		for _, combo := range combinations {
+			// Prune combinations that use an excluded element or combination
+			if !rules.allows(combo) {
+				continue
+			}
+			sink.report(Step{Kind: StepExpand, Element: current.Element, Item1: combo.Item1, Item2: combo.Item2, Depth: len(current.Path), NodesVisited: nodesVisited})
+
 			// Create new step
 			newStep := RecipeStep{
 				Result: current.Element,
@@ -735,6 +860,7 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 					Path:    newPath,
 					Explored: explored,
 				})
+				sink.report(Step{Kind: StepPush, Element: combo.Item1, Depth: len(newPath), NodesVisited: nodesVisited})
 			}
 			
 			if !isBasicElement(combo.Item2, basicElements) {
@@ -743,6 +869,7 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 					Path:    newPath,
 					Explored: explored,
 				})
+				sink.report(Step{Kind: StepPush, Element: combo.Item2, Depth: len(newPath), NodesVisited: nodesVisited})
 			}
 			
 			// If both ingredients are basic, we have a complete path
@@ -754,12 +881,15 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 				}
 				
 				// Only add if we haven't processed this exact recipe before
-				if !processedCombinations[recipeKey] {
+				// and it uses every required element
+				if processedCombinations[recipeKey] {
+					sink.dedup(newPath, nodesVisited)
+				} else if rules.satisfied(newPath) {
 					processedCombinations[recipeKey] = true
-					allRecipes = append(allRecipes, newPath)
+					sink.add(newPath, nodesVisited)
 					
 					// Check if we've found enough recipes
-					if len(allRecipes) >= maxRecipesToFind {
+					if sink.full() {
 						break
 					}
 				}
@@ -767,5 +897,5 @@ func findRecipesBidirectional(elementName string, basicElements []string, maxRec
 		}
 	}
 	
-	return allRecipes, nodesVisited
+	return nodesVisited
 }
\ No newline at end of file
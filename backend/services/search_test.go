@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSearchStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		element    string
+		recipeType string
+		maxRecipes int
+		status     SearchStatus
+		recipes    int
+	}{
+		{name: "all recipes", element: "Brick", recipeType: "All", status: StatusOK, recipes: 2},
+		{name: "limit above recipe count", element: "Brick", recipeType: "Limit", maxRecipes: 5, status: StatusOK, recipes: 2},
+		{name: "limit reached", element: "Brick", recipeType: "Limit", maxRecipes: 1, status: StatusLimitReached, recipes: 1},
+		{name: "one recipe", element: "Stone", recipeType: "One", status: StatusLimitReached, recipes: 1},
+		{name: "name in other case", element: "brick", recipeType: "All", status: StatusOK, recipes: 2},
+		{name: "unknown element", element: "Unicorn", recipeType: "All", status: StatusElementNotFound},
+		{name: "basic element", element: "Fire", recipeType: "All", status: StatusBasicElement},
+		{name: "no complete recipe", element: "Ghost", recipeType: "All", status: StatusNoRecipe},
+	}
+
+	searcher := newFixtureSearcher()
+	for _, algorithm := range Algorithms {
+		for _, tt := range tests {
+			t.Run(algorithm+"/"+tt.name, func(t *testing.T) {
+				result, err := searcher.Search(algorithm, tt.element, tt.recipeType, tt.maxRecipes, SearchOptions{})
+				if err != nil {
+					t.Fatalf("Search error: %v", err)
+				}
+				if result.Status != tt.status {
+					t.Errorf("status = %s, want %s", result.Status, tt.status)
+				}
+				recipes := 0
+				if HasRecipe(result.Results) {
+					recipes = len(result.Results)
+				}
+				if recipes != tt.recipes {
+					t.Errorf("recipes = %d, want %d", recipes, tt.recipes)
+				}
+			})
+		}
+	}
+}
+
+func TestSearchUnknownAlgorithm(t *testing.T) {
+	_, err := newFixtureSearcher().Search("A*", "Brick", "All", 0, SearchOptions{})
+	if !errors.Is(err, ErrUnknownAlgorithm) {
+		t.Errorf("error = %v, want %v", err, ErrUnknownAlgorithm)
+	}
+}
+
+// failingRepository fails the repository calls named in failing
+type failingRepository struct {
+	RecipeRepository
+	failing string
+}
+
+var errRepository = errors.New("dataset unavailable")
+
+func (r failingRepository) ElementExists(name string) (bool, error) {
+	if r.failing == "ElementExists" {
+		return false, errRepository
+	}
+	return r.RecipeRepository.ElementExists(name)
+}
+
+func (r failingRepository) BasicElements() ([]string, error) {
+	if r.failing == "BasicElements" {
+		return nil, errRepository
+	}
+	return r.RecipeRepository.BasicElements()
+}
+
+func (r failingRepository) Recipes(element string) ([]Combination, error) {
+	if r.failing == "Recipes" {
+		return nil, errRepository
+	}
+	return r.RecipeRepository.Recipes(element)
+}
+
+func TestSearchRepositoryError(t *testing.T) {
+	for _, failing := range []string{"ElementExists", "BasicElements", "Recipes"} {
+		searcher := NewSearcher(failingRepository{RecipeRepository: newFixtureRepository(), failing: failing})
+		for _, algorithm := range Algorithms {
+			t.Run(algorithm+"/"+failing, func(t *testing.T) {
+				_, err := searcher.Search(algorithm, "Brick", "All", 0, SearchOptions{})
+				if !errors.Is(err, errRepository) {
+					t.Errorf("error = %v, want %v", err, errRepository)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"main/data"
+)
+
+// Default dataset locations, relative to the backend working directory
+const (
+	defaultDatabasePath = "../database/alchemy.db"
+	defaultMapperPath   = "../database/mapper2.json"
+)
+
+// OpenDefaultCatalog opens the datasets configured through ALCHEMY_DB_PATH and
+// ALCHEMY_MAPPER_PATH. When neither is set and the binary was built with the
+// embedded dataset, it falls back to that instead of the default paths.
+func OpenDefaultCatalog() (*Catalog, error) {
+	dataPath := os.Getenv("ALCHEMY_DB_PATH")
+	mapperPath := os.Getenv("ALCHEMY_MAPPER_PATH")
+
+	if dataPath == "" && mapperPath == "" && data.Embedded() {
+		mapper, err := LoadMapper(bytes.NewReader(data.Mapper()))
+		if err != nil {
+			return nil, err
+		}
+		db, err := openEmbeddedDatabase(data.SQLite())
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("Database ditemukan (embedded)")
+		return NewSQLiteCatalog(db, mapper)
+	}
+
+	if dataPath == "" {
+		dataPath = defaultDatabasePath
+	}
+	if mapperPath == "" {
+		mapperPath = defaultMapperPath
+	}
+	catalog, err := OpenCatalog(dataPath, mapperPath)
+	if err == nil {
+		log.Printf("Database ditemukan: %s (dataset: %s)", dataPath, strings.Join(catalog.Names(), ", "))
+	}
+	return catalog, err
+}
+
+// OpenCatalog opens the datasets at dataPath. The mapper at mapperPath belongs to the
+// default dataset; an empty mapperPath opens the datasets without a mapper file.
+// Files ending in .csv are read as the scraper's alchemy.csv, files ending in
+// .json as an exported elements table (for example the output of
+// `sqlite3 -json alchemy.db "SELECT * FROM elements"`); both hold a single dataset.
+// Anything else is opened as a SQLite database, which may hold several datasets.
+func OpenCatalog(dataPath, mapperPath string) (*Catalog, error) {
+	mapper, err := loadMapperFile(mapperPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var repo RecipeRepository
+	switch strings.ToLower(filepath.Ext(dataPath)) {
+	case ".json":
+		file, err := os.Open(dataPath)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuka dataset: %w", err)
+		}
+		defer file.Close()
+		if repo, err = LoadJSONRepository(file, mapper); err != nil {
+			return nil, err
+		}
+	case ".csv":
+		file, err := os.Open(dataPath)
+		if err != nil {
+			return nil, fmt.Errorf("gagal membuka dataset: %w", err)
+		}
+		defer file.Close()
+		if repo, err = LoadCSVRepository(file, mapper); err != nil {
+			return nil, err
+		}
+	default:
+		db, err := openSQLiteDatabase(dataPath)
+		if err != nil {
+			return nil, err
+		}
+		return NewSQLiteCatalog(db, mapper)
+	}
+
+	catalog := NewCatalog()
+	catalog.Add(DefaultDataset, repo)
+	return catalog, nil
+}
+
+// OpenRepository opens one dataset stored at dataPath, see OpenCatalog. An empty
+// dataset name selects the default dataset.
+func OpenRepository(dataPath, mapperPath, dataset string) (RecipeRepository, error) {
+	catalog, err := OpenCatalog(dataPath, mapperPath)
+	if err != nil {
+		return nil, err
+	}
+	repo, ok := catalog.Repository(dataset)
+	if !ok {
+		return nil, fmt.Errorf("dataset %q tidak ada di %s", dataset, dataPath)
+	}
+	return repo, nil
+}
+
+// LoadJSONRepository reads an exported elements table (a JSON array of rows)
+// into an in-memory repository
+func LoadJSONRepository(r io.Reader, mapper map[string]string) (*MemoryRepository, error) {
+	var rows []ElementRow
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("gagal mendekode dataset JSON: %w", err)
+	}
+	return NewMemoryRepository(rows, mapper), nil
+}
+
+// LoadMapper decodes an element name -> icon URL mapper such as mapper2.json
+func LoadMapper(r io.Reader) (map[string]string, error) {
+	var mapper map[string]string
+	if err := json.NewDecoder(r).Decode(&mapper); err != nil {
+		return nil, fmt.Errorf("gagal mendekode mapper.json: %w", err)
+	}
+	return mapper, nil
+}
+
+// loadMapperFile decodes the mapper at path
+func loadMapperFile(path string) (map[string]string, error) {
+	if path == "" {
+		return map[string]string{}, nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka mapper.json: %w", err)
+	}
+	defer file.Close()
+	return LoadMapper(file)
+}
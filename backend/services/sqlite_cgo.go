@@ -0,0 +1,59 @@
+//go:build cgo
+
+package services
+
+import (
+	"database/sql"
+	"fmt"
+	"sync"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+// openSQLiteDatabase opens alchemy.db read-only
+func openSQLiteDatabase(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=ro")
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka database: %w", err)
+	}
+	return db, nil
+}
+
+// openPlayerDatabase opens players.db for writing, creating the file when missing.
+// A single connection serialises the writes of concurrent requests.
+func openPlayerDatabase(path string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite3", "file:"+path+"?mode=rwc&_busy_timeout=5000")
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka database pemain: %w", err)
+	}
+	db.SetMaxOpenConns(1)
+	return db, nil
+}
+
+var registerEmbeddedDriver sync.Once
+
+// openEmbeddedDatabase opens the embedded SQLite snapshot as an in-memory database.
+// Every pooled connection deserializes its own copy of the snapshot.
+func openEmbeddedDatabase(snapshot []byte) (*sql.DB, error) {
+	registerEmbeddedDriver.Do(func() {
+		sql.Register("sqlite3_embedded", &sqlite3.SQLiteDriver{
+			ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+				return conn.Deserialize(snapshot, "main")
+			},
+		})
+	})
+	db, err := sql.Open("sqlite3_embedded", ":memory:")
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("gagal membuka database: %w", err)
+	}
+	return db, nil
+}
@@ -0,0 +1,23 @@
+//go:build !cgo
+
+package services
+
+import (
+	"database/sql"
+	"errors"
+)
+
+// errSQLiteUnavailable is returned by builds without cgo, where go-sqlite3 cannot work
+var errSQLiteUnavailable = errors.New("dukungan SQLite membutuhkan cgo; gunakan dataset .csv atau .json lewat ALCHEMY_DB_PATH")
+
+func openSQLiteDatabase(path string) (*sql.DB, error) {
+	return nil, errSQLiteUnavailable
+}
+
+func openPlayerDatabase(path string) (*sql.DB, error) {
+	return nil, errSQLiteUnavailable
+}
+
+func openEmbeddedDatabase(snapshot []byte) (*sql.DB, error) {
+	return nil, errSQLiteUnavailable
+}
@@ -0,0 +1,192 @@
+package services
+
+import (
+	"database/sql"
+)
+
+// SQLiteRepository reads the recipe graph of one dataset from the elements table of alchemy.db
+type SQLiteRepository struct {
+	db      *sql.DB
+	dataset string // Empty when the table predates the dataset column
+	mapper  map[string]string
+}
+
+// NewSQLiteRepository creates a repository over one dataset of an opened alchemy.db and an
+// image mapper. An empty dataset reads every row, for databases without a dataset column.
+// Icons stored by the scraper in the images table fill in elements missing from the mapper.
+func NewSQLiteRepository(db *sql.DB, dataset string, mapper map[string]string) *SQLiteRepository {
+	r := &SQLiteRepository{db: db, dataset: dataset, mapper: make(map[string]string, len(mapper))}
+
+	query := "SELECT element, image FROM images"
+	if dataset != "" {
+		query += " WHERE dataset = ?"
+	}
+	if rows, err := db.Query(query, r.args()...); err == nil {
+		for rows.Next() {
+			var element, image string
+			if err := rows.Scan(&element, &image); err == nil {
+				r.mapper[element] = image
+			}
+		}
+		rows.Close()
+	}
+	for element, image := range mapper {
+		r.mapper[element] = image
+	}
+	return r
+}
+
+// filter returns the condition restricting a query to the repository's dataset
+func (r *SQLiteRepository) filter() string {
+	if r.dataset == "" {
+		return ""
+	}
+	return " AND dataset = ?"
+}
+
+// args appends the dataset argument used by filter to the query arguments
+func (r *SQLiteRepository) args(args ...any) []any {
+	if r.dataset == "" {
+		return args
+	}
+	return append(args, r.dataset)
+}
+
+// ElementExists reports whether the element has at least one row in the elements table
+func (r *SQLiteRepository) ElementExists(name string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM elements WHERE element = ?"+r.filter()+")", r.args(name)...).Scan(&exists)
+	return exists, err
+}
+
+// Elements returns every distinct element in the order it was first inserted
+func (r *SQLiteRepository) Elements() ([]string, error) {
+	var elements []string
+	rows, err := r.db.Query("SELECT element FROM elements WHERE element IS NOT NULL"+r.filter()+" GROUP BY element ORDER BY MIN(rowid)", r.args()...)
+	if err != nil {
+		return elements, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var element string
+		if err := rows.Scan(&element); err == nil {
+			elements = append(elements, element)
+		}
+	}
+	return elements, rows.Err()
+}
+
+// Recipes returns all direct combinations that create an element
+func (r *SQLiteRepository) Recipes(element string) ([]Combination, error) {
+	var combinations []Combination
+
+	rows, err := r.db.Query("SELECT item1, item2 FROM elements WHERE element = ? AND item1 IS NOT NULL AND item2 IS NOT NULL"+r.filter(), r.args(element)...)
+	if err != nil {
+		return combinations, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var combo Combination
+		if err := rows.Scan(&combo.Item1, &combo.Item2); err != nil {
+			continue
+		}
+		combinations = append(combinations, combo)
+	}
+
+	return combinations, rows.Err()
+}
+
+// Products returns all elements created with the ingredient on either side of a recipe
+func (r *SQLiteRepository) Products(ingredient string) ([]Product, error) {
+	var products []Product
+
+	rows, err := r.db.Query(`
+		SELECT item2, element FROM elements WHERE item1 = ? AND item2 IS NOT NULL`+r.filter()+`
+		UNION ALL
+		SELECT item1, element FROM elements WHERE item2 = ? AND item1 IS NOT NULL AND item1 <> item2`+r.filter(),
+		append(r.args(ingredient), r.args(ingredient)...)...)
+	if err != nil {
+		return products, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var product Product
+		if err := rows.Scan(&product.Partner, &product.Result); err != nil {
+			continue
+		}
+		products = append(products, product)
+	}
+
+	return products, rows.Err()
+}
+
+// BasicElements returns all elements that never appear with a complete recipe
+func (r *SQLiteRepository) BasicElements() ([]string, error) {
+	basicElements := []string{}
+	rows, err := r.db.Query("SELECT DISTINCT element FROM elements WHERE element NOT IN (SELECT DISTINCT element FROM elements WHERE item1 IS NOT NULL AND item2 IS NOT NULL"+r.filter()+")"+r.filter(),
+		append(r.args(), r.args()...)...)
+	if err != nil {
+		return basicElements, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var element string
+		if err := rows.Scan(&element); err == nil {
+			basicElements = append(basicElements, element)
+		}
+	}
+	return basicElements, rows.Err()
+}
+
+// ImageURL looks the element up in the image mapper
+func (r *SQLiteRepository) ImageURL(element string) string {
+	return r.mapper[element]
+}
+
+// NewSQLiteCatalog creates one repository per dataset stored in alchemy.db. Databases
+// written before the dataset column existed hold only the default dataset. The mapper
+// file belongs to the default dataset; other datasets use the icons in the images table.
+func NewSQLiteCatalog(db *sql.DB, mapper map[string]string) (*Catalog, error) {
+	catalog := NewCatalog()
+
+	var hasDataset bool
+	err := db.QueryRow("SELECT EXISTS(SELECT 1 FROM pragma_table_info('elements') WHERE name = 'dataset')").Scan(&hasDataset)
+	if err != nil {
+		return nil, err
+	}
+	if !hasDataset {
+		catalog.Add(DefaultDataset, NewSQLiteRepository(db, "", mapper))
+		return catalog, nil
+	}
+
+	rows, err := db.Query("SELECT DISTINCT dataset FROM elements")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var names []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, name := range names {
+		datasetMapper := map[string]string{}
+		if name == DefaultDataset {
+			datasetMapper = mapper
+		}
+		catalog.Add(name, NewSQLiteRepository(db, name, datasetMapper))
+	}
+	return catalog, nil
+}
@@ -0,0 +1,45 @@
+package services
+
+// SearchStatus classifies the outcome of a search so clients can tell an unknown
+// element from a basic one or from a search that found nothing
+type SearchStatus string
+
+// Search statuses. Only StatusOK and StatusLimitReached come with recipes.
+const (
+	StatusOK              SearchStatus = "OK"                // Every recipe the search found is returned
+	StatusLimitReached    SearchStatus = "LIMIT_REACHED"     // The search stopped at the requested number of recipes
+	StatusElementNotFound SearchStatus = "ELEMENT_NOT_FOUND" // The element is not in the dataset
+	StatusBasicElement    SearchStatus = "BASIC_ELEMENT"     // The element is a basic or owned element and needs no recipe
+	StatusNoRecipe        SearchStatus = "NO_RECIPE"         // The search ended without a complete recipe
+)
+
+// SearchResult is the outcome of one search
+type SearchResult struct {
+	Status        SearchStatus  `json:"status"`
+	Message       string        `json:"message"`
+	Results       []*RecipeTree `json:"results"`
+	NodesVisited  int           `json:"nodesVisited"`
+	ExecutionTime float64       `json:"executionTime"` // Milliseconds
+}
+
+// classify works out the status of a finished search that found the given number
+// of recipes and describes it
+func (s *Searcher) classify(elementName, recipeType string, maxRecipes int, opts SearchOptions, found int) (SearchStatus, string) {
+	if found > 0 {
+		if recipeType == "One" && found >= 1 || recipeType == "Limit" && found >= maxRecipes {
+			return StatusLimitReached, "Stopped after the requested number of recipes"
+		}
+		return StatusOK, "Recipes found"
+	}
+
+	if exists, err := s.repo.ElementExists(elementName); err == nil && !exists {
+		return StatusElementNotFound, "Element not found"
+	}
+	if isBasicElement(elementName, s.getBasicElements()) {
+		return StatusBasicElement, "This is a basic element"
+	}
+	if isBasicElement(elementName, opts.owned()) {
+		return StatusBasicElement, "This element is already owned"
+	}
+	return StatusNoRecipe, "No recipe found"
+}
@@ -0,0 +1,45 @@
+package services
+
+// StepKind names one event of a traversal
+type StepKind string
+
+// Traversal events reported to SearchOptions.OnStep
+const (
+	StepVisit  StepKind = "visit"  // A node was dequeued (BFS, bidirectional) or popped (DFS)
+	StepCycle  StepKind = "cycle"  // The node was skipped because it is already on its own path
+	StepExpand StepKind = "expand" // A combination of the node was expanded into its ingredients
+	StepPush   StepKind = "push"   // An ingredient was queued (BFS, bidirectional) or pushed (DFS)
+	StepDedup  StepKind = "dedup"  // A complete recipe was dropped because it was already found
+	StepRecipe StepKind = "recipe" // A complete recipe was found
+)
+
+// StepKinds lists every kind of step in the order a node usually goes through them
+var StepKinds = []StepKind{StepVisit, StepCycle, StepExpand, StepPush, StepDedup, StepRecipe}
+
+// Step is one event of a traversal
+type Step struct {
+	Kind         StepKind `json:"kind"`
+	Element      string   `json:"element"`         // The node the event is about, the target for recipes and dedups
+	Item1        string   `json:"item1,omitempty"` // Ingredients of an expanded combination
+	Item2        string   `json:"item2,omitempty"`
+	Depth        int      `json:"depth"`            // Combinations between the target and the node
+	NodesVisited int      `json:"nodesVisited"`     // Nodes visited so far
+	Recipe       []string `json:"recipe,omitempty"` // Steps of a found or dropped recipe
+}
+
+// report hands a traversal event to the OnStep callback of the search, if any.
+// Once the callback fails, later events are dropped and the traversal stops.
+func (r *recipeSink) report(step Step) {
+	if r.onStep == nil || r.err != nil {
+		return
+	}
+	r.err = r.onStep(step)
+}
+
+// dedup reports a complete recipe that was dropped because the traversal reached it before
+func (r *recipeSink) dedup(recipe []RecipeStep, nodesVisited int) {
+	if r.onStep == nil {
+		return // Skip formatting the recipe
+	}
+	r.report(Step{Kind: StepDedup, Element: recipe[0].Result, NodesVisited: nodesVisited, Recipe: formatRecipeSteps(recipe)})
+}
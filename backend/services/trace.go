@@ -0,0 +1,283 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Limits of the traces kept by the backend
+const (
+	DefaultTraceLimit    = 200000 // Events stored per trace, about 6 MB
+	DefaultTraceCapacity = 20     // Traces kept by a TraceStore
+)
+
+// TraceSummary describes a recorded search without its steps
+type TraceSummary struct {
+	ID           string           `json:"id"`
+	Dataset      string           `json:"dataset,omitempty"`
+	CreatedAt    time.Time        `json:"createdAt"`
+	Algorithm    string           `json:"algorithm"`
+	Element      string           `json:"element"`
+	RecipeType   string           `json:"recipeType"`
+	MaxRecipes   int              `json:"maxRecipes,omitempty"`
+	Status       SearchStatus     `json:"status"`
+	NodesVisited int              `json:"nodesVisited"`
+	Recipes      int              `json:"recipes"`         // Recipes the search found
+	Events       int              `json:"events"`          // Every event of the traversal, stored or not
+	Truncated    bool             `json:"truncated"`       // Some events were not stored: past the limit, or see Error
+	Error        string           `json:"error,omitempty"` // First event that could not be stored, and why
+	Counts       map[StepKind]int `json:"counts"`          // Events of each kind, stored or not
+}
+
+// TraceStep is one stored event of a trace
+type TraceStep struct {
+	Seq int `json:"seq"` // Numbers the events of the search from 1
+	Step
+}
+
+// Trace records the events of one search. Element names and recipe steps are stored
+// once and every event as a fixed-size record, so long traversals stay small; the
+// steps are only expanded when the trace is read or written as JSON.
+type Trace struct {
+	TraceSummary
+
+	limit   int
+	names   []string
+	index   map[string]int32
+	events  []traceEvent
+	recipes [][]int32
+}
+
+// traceEvent is a Step stored as indexes into the names and recipes of its trace.
+// Names are -1 when empty, recipe is -1 for events without a recipe.
+type traceEvent struct {
+	seq                   int32
+	kind                  uint8 // Index into StepKinds
+	element, item1, item2 int32
+	depth                 int32
+	nodesVisited          int32
+	recipe                int32
+}
+
+// NewTrace creates a trace that stores the first limit events of a search, or every
+// event when limit is 0. Pass it in SearchOptions.Trace.
+func NewTrace(limit int) *Trace {
+	return &Trace{
+		TraceSummary: TraceSummary{CreatedAt: time.Now().UTC(), Counts: map[StepKind]int{}},
+		limit:        limit,
+		index:        map[string]int32{},
+	}
+}
+
+// Len returns the number of stored steps
+func (t *Trace) Len() int {
+	return len(t.events)
+}
+
+// Step returns the i-th stored step
+func (t *Trace) Step(i int) TraceStep {
+	event := t.events[i]
+	step := TraceStep{Seq: int(event.seq), Step: Step{
+		Kind:         StepKinds[event.kind],
+		Element:      t.name(event.element),
+		Item1:        t.name(event.item1),
+		Item2:        t.name(event.item2),
+		Depth:        int(event.depth),
+		NodesVisited: int(event.nodesVisited),
+	}}
+	if event.recipe >= 0 {
+		recipe := t.recipes[event.recipe]
+		step.Recipe = make([]string, len(recipe))
+		for j, name := range recipe {
+			step.Recipe[j] = t.name(name)
+		}
+	}
+	return step
+}
+
+// Steps returns every stored step
+func (t *Trace) Steps() []TraceStep {
+	steps := make([]TraceStep, t.Len())
+	for i := range steps {
+		steps[i] = t.Step(i)
+	}
+	return steps
+}
+
+// record counts a traversal event and stores it while the trace is below its limit.
+// An event that cannot be stored marks the trace as truncated, and the first such
+// failure is kept in Error.
+func (t *Trace) record(step Step) {
+	t.Events++
+	t.Counts[step.Kind]++
+	if t.limit > 0 && len(t.events) >= t.limit {
+		t.Truncated = true
+		return
+	}
+	if err := t.store(t.Events, step); err != nil {
+		t.Truncated = true
+		if t.Error == "" {
+			t.Error = fmt.Sprintf("step %d: %v", t.Events, err)
+		}
+	}
+}
+
+// store appends an event with its sequence number
+func (t *Trace) store(seq int, step Step) error {
+	kind := -1
+	for i, known := range StepKinds {
+		if known == step.Kind {
+			kind = i
+		}
+	}
+	if kind < 0 {
+		return fmt.Errorf("unknown step kind %q", step.Kind)
+	}
+
+	event := traceEvent{
+		seq:          int32(seq),
+		kind:         uint8(kind),
+		element:      t.intern(step.Element),
+		item1:        t.intern(step.Item1),
+		item2:        t.intern(step.Item2),
+		depth:        int32(step.Depth),
+		nodesVisited: int32(step.NodesVisited),
+		recipe:       -1,
+	}
+	if step.Recipe != nil {
+		recipe := make([]int32, len(step.Recipe))
+		for i, line := range step.Recipe {
+			recipe[i] = t.intern(line)
+		}
+		t.recipes = append(t.recipes, recipe)
+		event.recipe = int32(len(t.recipes) - 1)
+	}
+	t.events = append(t.events, event)
+	return nil
+}
+
+// intern returns the index of name in the names of the trace, adding it if needed
+func (t *Trace) intern(name string) int32 {
+	if name == "" {
+		return -1
+	}
+	if i, ok := t.index[name]; ok {
+		return i
+	}
+	i := int32(len(t.names))
+	t.names = append(t.names, name)
+	t.index[name] = i
+	return i
+}
+
+// name returns the name stored at index i
+func (t *Trace) name(i int32) string {
+	if i < 0 {
+		return ""
+	}
+	return t.names[i]
+}
+
+// finish records the request and outcome of the traced search
+func (t *Trace) finish(algorithm string, elementName string, recipeType string, maxRecipes int, status SearchStatus, nodesVisited int, found int) {
+	t.Algorithm = algorithm
+	t.Element = elementName
+	t.RecipeType = recipeType
+	if recipeType == "Limit" {
+		t.MaxRecipes = maxRecipes
+	}
+	t.Status = status
+	t.NodesVisited = nodesVisited
+	t.Recipes = found
+}
+
+// traceJSON is the JSON form of a trace, with every stored step expanded
+type traceJSON struct {
+	TraceSummary
+	Steps []TraceStep `json:"steps"`
+}
+
+// MarshalJSON writes the summary of the trace followed by its steps
+func (t *Trace) MarshalJSON() ([]byte, error) {
+	return json.Marshal(traceJSON{TraceSummary: t.TraceSummary, Steps: t.Steps()})
+}
+
+// UnmarshalJSON reads a trace written by MarshalJSON, for example one downloaded
+// from the backend, so it can be replayed or compared
+func (t *Trace) UnmarshalJSON(data []byte) error {
+	var decoded traceJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	*t = *NewTrace(0)
+	t.TraceSummary = decoded.TraceSummary
+	if t.Counts == nil {
+		t.Counts = map[StepKind]int{}
+	}
+	for _, step := range decoded.Steps {
+		if err := t.store(step.Seq, step.Step); err != nil {
+			return fmt.Errorf("step %d: %w", step.Seq, err)
+		}
+	}
+	return nil
+}
+
+// onStep returns the callback the traversal reports its events to: the trace, if
+// any, then OnStep
+func (o SearchOptions) onStep() func(Step) error {
+	if o.Trace == nil {
+		return o.OnStep
+	}
+	trace, onStep := o.Trace, o.OnStep
+	return func(step Step) error {
+		trace.record(step)
+		if onStep == nil {
+			return nil
+		}
+		return onStep(step)
+	}
+}
+
+// TraceStore keeps the latest recorded traces in memory only. Once it is full the
+// oldest trace is dropped, and every trace is lost when the process restarts, so a
+// trace ID only works until then.
+type TraceStore struct {
+	mu       sync.Mutex
+	capacity int
+	traces   map[string]*Trace
+	order    []string // IDs from oldest to newest
+}
+
+// NewTraceStore creates a store that keeps up to capacity traces
+func NewTraceStore(capacity int) *TraceStore {
+	return &TraceStore{capacity: capacity, traces: map[string]*Trace{}}
+}
+
+// Add stores a finished trace under a new random ID and returns the ID
+func (s *TraceStore) Add(trace *Trace) string {
+	var b [8]byte
+	rand.Read(b[:])
+	trace.ID = hex.EncodeToString(b[:])
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.order) >= s.capacity {
+		delete(s.traces, s.order[0])
+		s.order = s.order[1:]
+	}
+	s.traces[trace.ID] = trace
+	s.order = append(s.order, trace.ID)
+	return trace.ID
+}
+
+// Get returns the trace stored under id
+func (s *TraceStore) Get(id string) (*Trace, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	trace, ok := s.traces[id]
+	return trace, ok
+}
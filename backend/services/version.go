@@ -0,0 +1,38 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// Version returns a hash of the searcher's dataset: every element with its icon
+// and recipes. It changes whenever a search could return something different,
+// so clients and proxies can use it to tell cached results apart.
+func (s *Searcher) Version() (string, error) {
+	s.versionOnce.Do(func() {
+		s.version, s.versionErr = datasetVersion(s.repo)
+	})
+	return s.version, s.versionErr
+}
+
+// datasetVersion hashes the dataset in repository order
+func datasetVersion(repo RecipeRepository) (string, error) {
+	elements, err := repo.Elements()
+	if err != nil {
+		return "", err
+	}
+	hash := sha256.New()
+	for _, element := range elements {
+		recipes, err := repo.Recipes(element)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s\x00%s\x00", element, repo.ImageURL(element))
+		for _, recipe := range recipes {
+			fmt.Fprintf(hash, "%s\x00%s\x00", recipe.Item1, recipe.Item2)
+		}
+		hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil))[:16], nil
+}
@@ -0,0 +1,234 @@
+// File ini berisi helper untuk membaca dan memvalidasi body JSON dan parameter query
+// dengan tag binding Gin.
+// Setiap field yang salah dilaporkan di errors dengan nama field JSON-nya.
+
+package utils
+
+import (
+    "encoding/json" // Untuk membaca body JSON
+    "errors"        // Untuk membedakan jenis error decode dan validasi
+    "io"            // Untuk mendeteksi body kosong
+    "net/http"      // Untuk kebutuhan HTTP response
+    "reflect"       // Untuk membaca tag json dan form dari struct
+    "sort"          // Untuk mengurutkan error parameter query
+    "strconv"       // Untuk membaca nama field dari pesan error dan parameter angka
+    "strings"       // Untuk menyusun pesan error
+    "unicode"       // Untuk mengubah nama field Go menjadi nama JSON
+
+    "github.com/gin-gonic/gin"               // Framework web Gin
+    "github.com/gin-gonic/gin/binding"       // Validator bawaan Gin
+    "github.com/go-playground/validator/v10" // Engine validator di balik tag binding
+)
+
+func init() {
+    // Laporkan nama field JSON (maxRecipes), bukan nama field Go (MaxRecipes)
+    if engine, ok := binding.Validator.Engine().(*validator.Validate); ok {
+        engine.RegisterTagNameFunc(jsonFieldName)
+    }
+}
+
+// jsonFieldName mengembalikan nama field di tag json, lalu tag form untuk parameter
+// query, atau nama field Go jika keduanya tidak ada
+func jsonFieldName(field reflect.StructField) string {
+    for _, tag := range []string{"json", "form"} {
+        name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+        if name == "-" {
+            return ""
+        }
+        if name != "" {
+            return name
+        }
+    }
+    return field.Name
+}
+
+// StrictParam adalah parameter query yang menyalakan mode strict, misalnya ?strict=true
+const StrictParam = "strict"
+
+// Strict melaporkan apakah request meminta mode strict
+func Strict(c *gin.Context) bool {
+    strict, _ := strconv.ParseBool(c.Query(StrictParam))
+    return strict
+}
+
+// BindJSON membaca body JSON ke obj lalu memeriksa tag binding-nya. Dalam mode strict
+// field yang tidak dikenal ditolak. Jika gagal, response 400 sudah dikirim dan hasilnya false.
+func BindJSON(c *gin.Context, obj interface{}, strict bool) bool {
+    if c.Request.Body == nil {
+        Error(c, http.StatusBadRequest, CodeInvalidRequest, "Request body is empty")
+        return false
+    }
+    decoder := json.NewDecoder(c.Request.Body)
+    if strict {
+        decoder.DisallowUnknownFields()
+    }
+    if err := decoder.Decode(obj); err != nil {
+        decodeFailed(c, err)
+        return false
+    }
+
+    return validate(c, obj)
+}
+
+// BindQuery membaca parameter query ke obj lewat tag form lalu memeriksa tag binding-nya.
+// Parameter angka yang bukan angka ditolak per parameter, dan dalam mode strict
+// parameter yang tidak dikenal juga ditolak. Jika gagal, response 400 sudah dikirim.
+func BindQuery(c *gin.Context, obj interface{}, strict bool) bool {
+    kinds := formKinds(reflect.TypeOf(obj))
+    var errs []FieldError
+    for key, values := range c.Request.URL.Query() {
+        kind, known := kinds[key]
+        switch {
+        case key == StrictParam:
+        case !known:
+            if strict {
+                errs = append(errs, FieldError{Field: key, Message: "is not a known parameter"})
+            }
+        case kind >= reflect.Int && kind <= reflect.Int64:
+            for _, value := range values {
+                if _, err := strconv.Atoi(value); err != nil {
+                    errs = append(errs, FieldError{Field: key, Message: "must be an integer"})
+                    break
+                }
+            }
+        }
+    }
+    if len(errs) > 0 {
+        sort.Slice(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field }) // Urutan map tidak tetap
+        ValidationFailed(c, errs)
+        return false
+    }
+
+    if err := binding.MapFormWithTag(obj, c.Request.URL.Query(), "form"); err != nil {
+        BadRequest(c)
+        return false
+    }
+    return validate(c, obj)
+}
+
+// formKinds memetakan nama di tag form ke jenis field-nya
+func formKinds(t reflect.Type) map[string]reflect.Kind {
+    if t.Kind() == reflect.Pointer {
+        t = t.Elem()
+    }
+    kinds := make(map[string]reflect.Kind)
+    for i := 0; i < t.NumField(); i++ {
+        name, _, _ := strings.Cut(t.Field(i).Tag.Get("form"), ",")
+        if name != "" && name != "-" {
+            kinds[name] = t.Field(i).Type.Kind()
+        }
+    }
+    return kinds
+}
+
+// validate memeriksa tag binding obj dan mengirim error 400 per field jika ada yang salah
+func validate(c *gin.Context, obj interface{}) bool {
+    if errs := ValidationErrors(obj); len(errs) > 0 {
+        ValidationFailed(c, errs)
+        return false
+    }
+    return true
+}
+
+// ValidationErrors memeriksa tag binding obj tanpa mengirim response, misalnya untuk
+// setiap item di request batch. Hasilnya kosong jika obj valid.
+func ValidationErrors(obj interface{}) []FieldError {
+    err := binding.Validator.ValidateStruct(obj)
+    var invalid validator.ValidationErrors
+    if errors.As(err, &invalid) {
+        errs := make([]FieldError, 0, len(invalid))
+        for _, fieldErr := range invalid {
+            errs = append(errs, FieldError{Field: fieldErr.Field(), Message: validationMessage(fieldErr)})
+        }
+        return errs
+    }
+    if err != nil {
+        return []FieldError{{Message: err.Error()}}
+    }
+    return nil
+}
+
+// decodeFailed mengirim error 400 untuk body yang tidak bisa dibaca
+func decodeFailed(c *gin.Context, err error) {
+    code, message, errs := DecodeError(err)
+    Error(c, http.StatusBadRequest, code, message, errs...)
+}
+
+// DecodeError menjelaskan error dari encoding/json sebagai code, pesan, dan error per
+// field. Tipe yang salah dan field yang tidak dikenal dilaporkan per field.
+func DecodeError(err error) (string, string, []FieldError) {
+    var typeErr *json.UnmarshalTypeError
+    switch {
+    case errors.Is(err, io.EOF):
+        return CodeInvalidRequest, "Request body is empty", nil
+    case errors.As(err, &typeErr):
+        return CodeValidationFailed, "Request validation failed", []FieldError{{Field: typeErr.Field, Message: "must be " + jsonTypeName(typeErr.Type)}}
+    case strings.HasPrefix(err.Error(), "json: unknown field "):
+        // encoding/json tidak punya tipe error sendiri untuk field yang tidak dikenal
+        field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), "json: unknown field "))
+        if unquoteErr == nil {
+            return CodeValidationFailed, "Request validation failed", []FieldError{{Field: field, Message: "is not a known field"}}
+        }
+    }
+    return CodeInvalidRequest, "Invalid request body", nil
+}
+
+// jsonTypeName menyebut tipe Go dengan nama tipe JSON, misalnya "a number"
+func jsonTypeName(t reflect.Type) string {
+    switch t.Kind() {
+    case reflect.String:
+        return "a string"
+    case reflect.Bool:
+        return "a boolean"
+    case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+        reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+        return "an integer"
+    case reflect.Float32, reflect.Float64:
+        return "a number"
+    case reflect.Slice, reflect.Array:
+        return "an array"
+    }
+    return "an object"
+}
+
+// validationMessage menjelaskan tag binding yang gagal dalam satu kalimat pendek
+func validationMessage(err validator.FieldError) string {
+    switch err.Tag() {
+    case "required":
+        return "is required"
+    case "required_if":
+        params := strings.Fields(err.Param())
+        if len(params) == 2 {
+            return "is required when " + lowerFirst(params[0]) + " is " + params[1]
+        }
+        return "is required"
+    case "oneof":
+        return "must be one of " + strings.Join(strings.Fields(err.Param()), ", ")
+    case "min", "gte":
+        return "must be at least " + err.Param() + lengthUnit(err.Kind())
+    case "max", "lte":
+        return "must be at most " + err.Param() + lengthUnit(err.Kind())
+    }
+    return "failed the " + err.Tag() + " check"
+}
+
+// lengthUnit menjelaskan bahwa batas min/max pada string dan array adalah panjangnya
+func lengthUnit(kind reflect.Kind) string {
+    switch kind {
+    case reflect.String:
+        return " characters long"
+    case reflect.Slice, reflect.Array, reflect.Map:
+        return " items long"
+    }
+    return ""
+}
+
+// lowerFirst mengubah nama field Go di parameter tag (RecipeType) menjadi nama JSON (recipeType)
+func lowerFirst(name string) string {
+    runes := []rune(name)
+    if len(runes) == 0 {
+        return name
+    }
+    runes[0] = unicode.ToLower(runes[0])
+    return string(runes)
+}
@@ -0,0 +1,52 @@
+// File ini berisi helper untuk response yang boleh di-cache browser dan proxy (nginx).
+// ETag dibuat dari versi data dan isi request, jadi request yang sama mendapat ETag
+// yang sama selama datanya tidak berubah.
+
+package utils
+
+import (
+    "crypto/sha256" // Untuk membuat ETag
+    "encoding/hex"  // Untuk menulis ETag sebagai teks
+    "net/http"      // Untuk status 304
+    "strings"       // Untuk membaca header If-None-Match
+
+    "github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// WeakETag membuat ETag lemah dari bagian-bagian yang menentukan isi response.
+// ETag lemah karena body tetap berbeda per request (requestId, executionTime).
+func WeakETag(parts ...string) string {
+    hash := sha256.New()
+    for _, part := range parts {
+        hash.Write([]byte(part))
+        hash.Write([]byte{0})
+    }
+    return `W/"` + hex.EncodeToString(hash.Sum(nil))[:20] + `"`
+}
+
+// NotModified memasang header ETag dan Cache-Control. Jika If-None-Match dari client
+// cocok dengan etag, response 304 tanpa body dikirim dan hasilnya true.
+func NotModified(c *gin.Context, etag string, cacheControl string) bool {
+    c.Header("ETag", etag)
+    c.Header("Cache-Control", cacheControl)
+    if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+        return false
+    }
+    c.Status(http.StatusNotModified)
+    c.Writer.WriteHeaderNow()
+    return true
+}
+
+// etagMatches membandingkan If-None-Match dengan etag secara lemah (awalan W/ diabaikan)
+func etagMatches(ifNoneMatch string, etag string) bool {
+    if ifNoneMatch == "" {
+        return false
+    }
+    for _, candidate := range strings.Split(ifNoneMatch, ",") {
+        candidate = strings.TrimSpace(candidate)
+        if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,38 @@
+package utils
+
+import "testing"
+
+func TestWeakETag(t *testing.T) {
+	etag := WeakETag("v1", "element=Brick")
+	if etag != WeakETag("v1", "element=Brick") {
+		t.Errorf("ETag untuk input yang sama harus sama")
+	}
+	// Pemisah antar bagian mencegah "ab"+"c" sama dengan "a"+"bc"
+	if WeakETag("ab", "c") == WeakETag("a", "bc") {
+		t.Errorf("ETag tidak boleh bergantung hanya pada gabungan bagian")
+	}
+	if etag == WeakETag("v2", "element=Brick") {
+		t.Errorf("ETag harus berubah jika versi dataset berubah")
+	}
+}
+
+func TestEtagMatches(t *testing.T) {
+	const etag = `W/"abc"`
+	tests := []struct {
+		ifNoneMatch string
+		want        bool
+	}{
+		{ifNoneMatch: "", want: false},
+		{ifNoneMatch: `W/"abc"`, want: true},
+		{ifNoneMatch: `"abc"`, want: true},
+		{ifNoneMatch: `W/"xyz", W/"abc"`, want: true},
+		{ifNoneMatch: "*", want: true},
+		{ifNoneMatch: `W/"xyz"`, want: false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.ifNoneMatch, etag); got != tt.want {
+			t.Errorf("etagMatches(%q) = %v, ingin %v", tt.ifNoneMatch, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+// File ini berisi helper untuk response NDJSON (satu objek JSON per baris), dipakai
+// endpoint yang mengirim hasil sedikit demi sedikit tanpa menunggu semuanya selesai.
+
+package utils
+
+import (
+    "encoding/json" // Untuk menulis setiap baris
+    "net/http"      // Untuk status response
+    "strings"       // Untuk membaca header Accept
+
+    "github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// NDJSONContentType adalah content type response NDJSON
+const NDJSONContentType = "application/x-ndjson"
+
+// WantsNDJSON melaporkan apakah client meminta NDJSON lewat header Accept
+func WantsNDJSON(c *gin.Context) bool {
+    return strings.Contains(c.GetHeader("Accept"), NDJSONContentType)
+}
+
+// StartNDJSON mengirim header response NDJSON dan mengembalikan fungsi yang menulis
+// satu baris lalu langsung mengirimnya ke client
+func StartNDJSON(c *gin.Context) func(line interface{}) error {
+    c.Header("Content-Type", NDJSONContentType)
+    c.Header("X-Content-Type-Options", "nosniff")
+    c.Header("X-Accel-Buffering", "no") // nginx tidak menahan baris di buffer
+    c.Status(http.StatusOK)
+    encoder := json.NewEncoder(c.Writer)
+    return func(line interface{}) error {
+        if err := encoder.Encode(line); err != nil {
+            return err
+        }
+        c.Writer.Flush()
+        return nil
+    }
+}
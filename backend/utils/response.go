@@ -1,30 +1,146 @@
 // File ini adalah utilitas untuk response standar API di backend Little Alchemy 2.
-// Berisi struct dan fungsi untuk mengirim response JSON yang konsisten.
+// Semua handler mengirim envelope yang sama lewat helper di sini, sehingga client
+// cukup membaca status, code, dan data tanpa menebak bentuk tiap endpoint.
+// Skema JSON envelope ada di response.schema.json dan disajikan di /api/schema/response.json.
 
 package utils
 
 import (
-    "encoding/json" // Untuk encode JSON
+    "crypto/rand"   // Untuk membuat request ID
+    _ "embed"       // Untuk menyertakan skema envelope
+    "encoding/hex"  // Untuk menulis request ID sebagai teks
     "net/http"      // Untuk kebutuhan HTTP response
+
+    "github.com/gin-gonic/gin" // Framework web Gin
+)
+
+// Nilai field status di envelope
+const (
+    StatusSuccess = "success" // Permintaan berhasil (HTTP < 400)
+    StatusError   = "error"   // Permintaan gagal (HTTP >= 400)
+)
+
+// Kode mesin yang dipakai handler. Status pencarian (LIMIT_REACHED, BASIC_ELEMENT,
+// ELEMENT_NOT_FOUND, NO_RECIPE) dari package services juga dipakai sebagai code.
+const (
+    CodeOK                   = "OK"
+    CodeInvalidRequest       = "INVALID_REQUEST"        // Body bukan JSON yang valid
+    CodeValidationFailed     = "VALIDATION_FAILED"      // Ada field yang salah, lihat errors
+    CodeUnknownDataset       = "UNKNOWN_DATASET"
+    CodeElementNotFound      = "ELEMENT_NOT_FOUND"
+    CodeUnknownElements      = "UNKNOWN_ELEMENTS"       // Nama elemen di field request tidak ada di dataset, lihat errors
+    CodePlayerNotFound       = "PLAYER_NOT_FOUND"
+    CodePlayersDisabled      = "PLAYERS_DISABLED"       // players.db tidak bisa dibuka
+    CodeElementNotDiscovered = "ELEMENT_NOT_DISCOVERED" // Pemain belum punya elemen yang dipakai
+    CodeDatasetConflict      = "DATASET_CONFLICT"       // Profil milik dataset lain
+    CodeNotFound             = "NOT_FOUND"              // Route tidak ada
+    CodeBudgetExhausted      = "BUDGET_EXHAUSTED"       // Waktu batch habis sebelum pencarian dimulai
+    CodeTraceNotFound        = "TRACE_NOT_FOUND"        // Trace tidak ada atau sudah digantikan trace yang lebih baru
+    CodeInternal             = "INTERNAL_ERROR"
 )
 
-// Response adalah struct standar untuk response API
+// requestIDKey adalah key request ID di gin.Context
+const requestIDKey = "requestId"
+
+// RequestIDHeader adalah header yang membawa request ID dari client dan ke client
+const RequestIDHeader = "X-Request-ID"
+
+// Response adalah envelope standar untuk semua response API
 type Response struct {
-    Status  string      `json:"status"`             // Status response (success/error)
-    Message string      `json:"message"`            // Pesan response
-    Data    interface{} `json:"data,omitempty"`     // Data (opsional)
+    Status    string       `json:"status"`           // success atau error
+    Code      string       `json:"code"`             // Kode mesin, misalnya OK atau ELEMENT_NOT_FOUND
+    Message   string       `json:"message"`          // Pesan untuk manusia
+    Errors    []FieldError `json:"errors,omitempty"` // Error per field jika validasi gagal
+    RequestID string       `json:"requestId"`        // ID request, sama dengan header X-Request-ID
+    Data      interface{}  `json:"data,omitempty"`   // Data (opsional)
+}
+
+// FieldError menjelaskan satu field request yang tidak valid
+type FieldError struct {
+    Field   string `json:"field"`   // Nama field di JSON, misalnya maxRecipes
+    Message string `json:"message"` // Alasan field ditolak
+}
+
+// ResponseSchema adalah JSON Schema dari envelope Response
+//
+//go:embed response.schema.json
+var ResponseSchema []byte
+
+// RequestID adalah middleware yang memberi setiap request sebuah ID. ID dari header
+// X-Request-ID dipakai ulang jika ada, supaya bisa dilacak lintas service.
+func RequestID() gin.HandlerFunc {
+    return func(c *gin.Context) {
+        id := c.GetHeader(RequestIDHeader)
+        if id == "" || len(id) > 128 {
+            id = newRequestID()
+        }
+        c.Set(requestIDKey, id)
+        c.Header(RequestIDHeader, id)
+        c.Next()
+    }
+}
+
+// newRequestID membuat ID acak 16 karakter hex
+func newRequestID() string {
+    var b [8]byte
+    rand.Read(b[:])
+    return hex.EncodeToString(b[:])
 }
 
-// SendResponse mengirim response JSON ke client
-func SendResponse(w http.ResponseWriter, statusCode int, status string, message string, data interface{}) {
-    w.Header().Set("Content-Type", "application/json") // Set header tipe konten
-    w.WriteHeader(statusCode)                          // Set status code
+// GetRequestID mengembalikan ID request yang diberikan middleware RequestID
+func GetRequestID(c *gin.Context) string {
+    return c.GetString(requestIDKey)
+}
 
-    response := Response{
-        Status:  status,   // Status response
-        Message: message,  // Pesan response
-        Data:    data,     // Data (jika ada)
+// Send mengirim envelope dengan status HTTP dan code tertentu. Status envelope
+// mengikuti status HTTP.
+func Send(c *gin.Context, httpStatus int, code string, message string, data interface{}, errs ...FieldError) {
+    status := StatusSuccess
+    if httpStatus >= http.StatusBadRequest {
+        status = StatusError
     }
+    c.JSON(httpStatus, Response{
+        Status:    status,
+        Code:      code,
+        Message:   message,
+        Errors:    errs,
+        RequestID: GetRequestID(c),
+        Data:      data,
+    })
+}
+
+// Success mengirim data dengan HTTP 200 dan code OK
+func Success(c *gin.Context, data interface{}) {
+    Send(c, http.StatusOK, CodeOK, "Success", data)
+}
+
+// Error mengirim envelope error tanpa data
+func Error(c *gin.Context, httpStatus int, code string, message string, errs ...FieldError) {
+    Send(c, httpStatus, code, message, nil, errs...)
+}
+
+// BadRequest mengirim error 400 untuk body yang tidak bisa dibaca
+func BadRequest(c *gin.Context) {
+    Error(c, http.StatusBadRequest, CodeInvalidRequest, "Invalid request body")
+}
 
-    json.NewEncoder(w).Encode(response) // Encode dan kirim response JSON
-}
\ No newline at end of file
+// ValidationFailed mengirim error 400 beserta daftar field yang salah
+func ValidationFailed(c *gin.Context, errs []FieldError) {
+    Error(c, http.StatusBadRequest, CodeValidationFailed, "Request validation failed", errs...)
+}
+
+// InternalError mengirim error 500 tanpa membocorkan detail error ke client
+func InternalError(c *gin.Context, message string, err error) {
+    c.Error(err) // Dicatat oleh logger gin
+    Error(c, http.StatusInternalServerError, CodeInternal, message)
+}
+
+// NoRoute adalah handler untuk route yang tidak ada
+func NoRoute(c *gin.Context) {
+    Error(c, http.StatusNotFound, CodeNotFound, "Route not found")
+}
+
+// Schema adalah handler yang menyajikan JSON Schema envelope
+func Schema(c *gin.Context) {
+    c.Data(http.StatusOK, "application/schema+json", ResponseSchema)
+}
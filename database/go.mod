@@ -1,3 +1,13 @@
 module scrape_elements
 
 go 1.24.3
+
+require (
+	github.com/PuerkitoBio/goquery v1.10.3
+	github.com/mattn/go-sqlite3 v1.14.28
+)
+
+require (
+	github.com/andybalholm/cascadia v1.3.3 // indirect
+	golang.org/x/net v0.39.0 // indirect
+)
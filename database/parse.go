@@ -0,0 +1,214 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Record adalah satu baris tabel elements: elemen beserta satu resepnya.
+// Elemen dasar (tanpa resep) punya Item1 dan Item2 kosong.
+type Record struct {
+	Element string
+	Item1   string
+	Item2   string
+	Pack    string // Paket konten resep, misalnya mythsPack; kosong untuk game dasar
+}
+
+// Dataset adalah hasil scrape: semua resep dan URL ikon setiap elemen
+type Dataset struct {
+	Records []Record
+	Images  map[string]string // Nama elemen -> URL ikon SVG
+}
+
+// Paket konten yang resepnya ditandai di wiki, misalnya "Earth + Rain (Myths and Monsters)"
+const mythsPack = "Myths and Monsters"
+
+// key adalah identitas unik record, sama dengan unique index di database
+func (r Record) key() string {
+	return r.Element + "\x00" + r.Item1 + "\x00" + r.Item2
+}
+
+// Error saat halaman tidak berisi data yang dikenali, biasanya karena layout wiki berubah
+var (
+	errNoTables  = errors.New("tidak ada tabel elemen di bawah judul tier; layout wiki mungkin berubah")
+	errNoRecipes = errors.New("tabel elemen ditemukan tetapi tidak ada resep yang terbaca; layout wiki mungkin berubah")
+)
+
+// Penanda paket di teks resep. Penanda diambil lebih dulu supaya resepnya bisa
+// dipisahkan ke dataset paket, baru sisa catatannya dibuang oleh trailingNote.
+var packNote = regexp.MustCompile(`(?i)\s*\(\s*myths and monsters\s*\)`)
+
+// Catatan tambahan di ujung nama bahan, misalnya catatan kaki "Earth [1]"
+var trailingNote = regexp.MustCompile(`\s*[(\[].*$`)
+
+// scrapeFile membaca satu file HTML wiki dan mengambil semua resep serta ikon di dalamnya
+func scrapeFile(path string) (Dataset, error) {
+	// Buka file HTML lokal
+	f, err := os.Open(path)
+	if err != nil {
+		return Dataset{}, err
+	}
+	defer f.Close()
+
+	return ParseElements(f)
+}
+
+// ParseElements membaca halaman HTML "Elements (Little Alchemy 2)" dari wiki dan
+// mengambil resep serta ikon dari setiap tabel di bawah judul tier (h3).
+// Halaman tanpa tabel atau tanpa satu pun resep dianggap error.
+func ParseElements(r io.Reader) (Dataset, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
+	if err != nil {
+		return Dataset{}, fmt.Errorf("gagal membuat dokumen dari file: %w", err)
+	}
+
+	dataset := Dataset{Images: make(map[string]string)}
+	tables, recipes := 0, 0
+
+	doc.Find("h3").Each(func(_ int, s *goquery.Selection) {
+		headline := s.Find(".mw-headline")
+		if _, exists := headline.Attr("id"); !exists {
+			return
+		}
+
+		// Satu tier bisa terdiri dari beberapa tabel sampai judul berikutnya
+		s.NextUntil("h2, h3").Filter("table").Each(func(_ int, table *goquery.Selection) {
+			tables++
+			table.Find("tr").Each(func(_ int, tr *goquery.Selection) {
+				tds := tr.Find("td")
+				if tds.Length() < 2 {
+					return // Baris header (th) atau baris tidak lengkap
+				}
+
+				element := cleanText(tds.Eq(0))
+				if element == "" {
+					return
+				}
+				if image := iconURL(tds.Eq(0)); image != "" {
+					dataset.Images[element] = image
+				}
+
+				liFound := false
+				tds.Eq(1).Find("li").Each(func(_ int, li *goquery.Selection) {
+					text, pack := splitPack(cleanText(li))
+					item1, item2, ok := splitRecipe(text)
+					if !ok {
+						return
+					}
+					dataset.Records = append(dataset.Records, Record{Element: element, Item1: item1, Item2: item2, Pack: pack})
+					liFound = true
+					recipes++
+				})
+
+				if !liFound {
+					// Tidak ada resep dalam <li>, simpan sebagai elemen tanpa resep
+					dataset.Records = append(dataset.Records, Record{Element: element})
+				}
+			})
+		})
+	})
+
+	if tables == 0 {
+		return dataset, errNoTables
+	}
+	if recipes == 0 {
+		return dataset, errNoRecipes
+	}
+	return dataset, nil
+}
+
+// cleanText mengambil teks sel tanpa catatan kaki (<sup>) dan dengan spasi dirapikan
+func cleanText(s *goquery.Selection) string {
+	s = s.Clone()
+	s.Find("sup").Remove()
+	return strings.Join(strings.Fields(s.Text()), " ") // Fields juga memecah &nbsp;
+}
+
+// splitPack membuang penanda paket dari teks resep dan mengembalikan nama paketnya,
+// atau string kosong untuk resep game dasar
+func splitPack(text string) (string, string) {
+	if !packNote.MatchString(text) {
+		return text, ""
+	}
+	return packNote.ReplaceAllString(text, ""), mythsPack
+}
+
+// splitRecipe memecah teks "Item1 + Item2" menjadi dua bahan. Teks tanpa tepat
+// satu '+' atau dengan bahan kosong bukan resep.
+func splitRecipe(text string) (string, string, bool) {
+	parts := strings.Split(text, "+")
+	if len(parts) != 2 {
+		return "", "", false
+	}
+	item1 := strings.TrimSpace(trailingNote.ReplaceAllString(parts[0], ""))
+	item2 := strings.TrimSpace(trailingNote.ReplaceAllString(parts[1], ""))
+	if item1 == "" || item2 == "" {
+		return "", "", false
+	}
+	return item1, item2, true
+}
+
+// iconURL mengambil URL ikon SVG dari sel elemen. Wiki memakai lazy loading,
+// sehingga URL asli ada di data-src dan src hanya berisi placeholder.
+func iconURL(cell *goquery.Selection) string {
+	url := ""
+	cell.Find("img").EachWithBreak(func(_ int, img *goquery.Selection) bool {
+		for _, attr := range []string{"data-src", "src"} {
+			src, _ := img.Attr(attr)
+			if strings.Contains(src, "little-alchemy/images") && strings.Contains(src, ".svg") {
+				url = strings.Split(src, "/revision/")[0] // Memotong URL setelah "/revision/"
+				return false
+			}
+		}
+		return true
+	})
+	return url
+}
+
+// uniqueRecords membuang record duplikat dengan tetap menjaga urutan
+func uniqueRecords(records []Record) []Record {
+	seen := make(map[string]bool, len(records))
+	unique := records[:0]
+	for _, record := range records {
+		if seen[record.key()] {
+			continue
+		}
+		seen[record.key()] = true
+		unique = append(unique, record)
+	}
+	return unique
+}
+
+// packDataset mengambil isi dataset untuk satu paket. Paket kosong berarti game dasar
+// saja; paket lain dibangun di atas game dasar, jadi berisi resep game dasar ditambah
+// resep paket tersebut. Ikon hanya diambil untuk elemen yang ada di dataset hasilnya.
+func packDataset(dataset Dataset, pack string) Dataset {
+	result := Dataset{Images: make(map[string]string)}
+	for _, record := range dataset.Records {
+		if record.Pack != "" && record.Pack != pack {
+			continue
+		}
+		result.Records = append(result.Records, record)
+		if image, ok := dataset.Images[record.Element]; ok {
+			result.Images[record.Element] = image
+		}
+	}
+	result.Records = uniqueRecords(result.Records)
+	return result
+}
+
+// hasPack melaporkan apakah ada resep milik paket di dataset
+func hasPack(dataset Dataset, pack string) bool {
+	for _, record := range dataset.Records {
+		if record.Pack == pack {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// parseFixture mem-parse file HTML dari folder testdata
+func parseFixture(t *testing.T, name string) (Dataset, error) {
+	t.Helper()
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("gagal membuka fixture %s: %v", name, err)
+	}
+	defer f.Close()
+	return ParseElements(f)
+}
+
+func TestParseElements(t *testing.T) {
+	tests := []struct {
+		fixture string
+		records []Record
+		images  map[string]string
+	}{
+		{
+			fixture: "basic.html",
+			records: []Record{
+				{Element: "Air"},
+				{Element: "Water"},
+				{Element: "Steam", Item1: "Air", Item2: "Fire"},
+				{Element: "Steam", Item1: "Water", Item2: "Fire"},
+			},
+			images: map[string]string{
+				"Air":   "https://static.wikia.nocookie.net/little-alchemy/images/0/03/Air_2.svg",
+				"Water": "https://static.wikia.nocookie.net/little-alchemy/images/f/f4/Water_2.svg",
+				"Steam": "https://static.wikia.nocookie.net/little-alchemy/images/6/6e/Steam_2.svg",
+			},
+		},
+		{
+			fixture: "special_characters.html",
+			records: []Record{
+				{Element: "Piñata", Item1: "Candy", Item2: "Container"},
+				{Element: "Jack-o'-lantern", Item1: "Pumpkin", Item2: "Light"},
+				{Element: "Acid rain", Item1: "Rain", Item2: "Smoke"},
+				{Element: "Fish & chips", Item1: "Fish", Item2: "French fries"},
+			},
+			images: map[string]string{},
+		},
+		{
+			fixture: "no_recipes.html",
+			records: []Record{
+				{Element: "Earth"},
+				{Element: "Fire"},
+				{Element: "Time"},
+				{Element: "Lava", Item1: "Earth", Item2: "Fire"},
+			},
+			images: map[string]string{},
+		},
+		{
+			fixture: "stray_text.html",
+			records: []Record{
+				{Element: "Mud", Item1: "Water", Item2: "Earth"},
+				{Element: "Mud", Item1: "Earth", Item2: "Rain", Pack: mythsPack},
+				{Element: "Dust", Item1: "Earth", Item2: "Air"},
+			},
+			images: map[string]string{},
+		},
+		{
+			fixture: "multiple_tables.html",
+			records: []Record{
+				{Element: "Dust", Item1: "Earth", Item2: "Air"},
+				{Element: "Energy", Item1: "Fire", Item2: "Fire"},
+				{Element: "Dust", Item1: "Earth", Item2: "Air"},
+				{Element: "Sand", Item1: "Stone", Item2: "Air"},
+			},
+			images: map[string]string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			dataset, err := parseFixture(t, tt.fixture)
+			if err != nil {
+				t.Fatalf("ParseElements error: %v", err)
+			}
+			if !reflect.DeepEqual(dataset.Records, tt.records) {
+				t.Errorf("records\n got: %q\nwant: %q", dataset.Records, tt.records)
+			}
+			if !reflect.DeepEqual(dataset.Images, tt.images) {
+				t.Errorf("images\n got: %q\nwant: %q", dataset.Images, tt.images)
+			}
+		})
+	}
+}
+
+func TestParseElementsLayoutChange(t *testing.T) {
+	tests := []struct {
+		fixture string
+		err     error
+	}{
+		{fixture: "layout_no_headlines.html", err: errNoTables},
+		{fixture: "layout_no_lists.html", err: errNoRecipes},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			if _, err := parseFixture(t, tt.fixture); !errors.Is(err, tt.err) {
+				t.Errorf("error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestUniqueRecords(t *testing.T) {
+	records := []Record{
+		{Element: "Dust", Item1: "Earth", Item2: "Air"},
+		{Element: "Air"},
+		{Element: "Dust", Item1: "Earth", Item2: "Air"},
+		{Element: "Dust", Item1: "Air", Item2: "Earth"},
+		{Element: "Air"},
+	}
+	want := []Record{
+		{Element: "Dust", Item1: "Earth", Item2: "Air"},
+		{Element: "Air"},
+		{Element: "Dust", Item1: "Air", Item2: "Earth"},
+	}
+	if got := uniqueRecords(records); !reflect.DeepEqual(got, want) {
+		t.Errorf("uniqueRecords\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestPackDataset(t *testing.T) {
+	dataset := Dataset{
+		Records: []Record{
+			{Element: "Earth"},
+			{Element: "Mud", Item1: "Water", Item2: "Earth"},
+			{Element: "Mud", Item1: "Earth", Item2: "Rain", Pack: mythsPack},
+			{Element: "Golem", Item1: "Mud", Item2: "Life", Pack: mythsPack},
+			{Element: "Mud", Item1: "Water", Item2: "Earth", Pack: mythsPack},
+		},
+		Images: map[string]string{"Earth": "earth.svg", "Golem": "golem.svg"},
+	}
+
+	tests := []struct {
+		pack    string
+		records []Record
+		images  map[string]string
+	}{
+		{
+			pack: "",
+			records: []Record{
+				{Element: "Earth"},
+				{Element: "Mud", Item1: "Water", Item2: "Earth"},
+			},
+			images: map[string]string{"Earth": "earth.svg"},
+		},
+		{
+			pack: mythsPack,
+			records: []Record{
+				{Element: "Earth"},
+				{Element: "Mud", Item1: "Water", Item2: "Earth"},
+				{Element: "Mud", Item1: "Earth", Item2: "Rain", Pack: mythsPack},
+				{Element: "Golem", Item1: "Mud", Item2: "Life", Pack: mythsPack},
+			},
+			images: map[string]string{"Earth": "earth.svg", "Golem": "golem.svg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pack, func(t *testing.T) {
+			got := packDataset(dataset, tt.pack)
+			if !reflect.DeepEqual(got.Records, tt.records) {
+				t.Errorf("records\n got: %q\nwant: %q", got.Records, tt.records)
+			}
+			if !reflect.DeepEqual(got.Images, tt.images) {
+				t.Errorf("images\n got: %q\nwant: %q", got.Images, tt.images)
+			}
+		})
+	}
+}
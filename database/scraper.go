@@ -1,130 +1,142 @@
 package main
 
 import (
-	"encoding/csv"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+)
 
-	"database/sql"
+// Nama file HTML default (halaman wiki yang disimpan dari browser)
+const defaultInput = "Elements (Little Alchemy 2) _ Little Alchemy Wiki _ Fandom.html"
 
-	"github.com/PuerkitoBio/goquery"
-	_ "github.com/mattn/go-sqlite3" // Untuk SQLite
-)
+// inputList menampung flag -in yang boleh diberikan berkali-kali
+type inputList []string
+
+func (l *inputList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+func (l *inputList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
 
 func main() {
-	// Buka file HTML lokal
-	f, err := os.Open("Elements (Little Alchemy 2) _ Little Alchemy Wiki _ Fandom.html")
-	if err != nil {
-		log.Fatalf("Gagal membuka file HTML: %v", err)
+	var inputs inputList
+	flag.Var(&inputs, "in", "file HTML wiki yang akan di-scrape (boleh diulang)")
+	dbPath := flag.String("db", "alchemy.db", "path database SQLite keluaran (kosongkan untuk melewati)")
+	csvPath := flag.String("csv", "", "path file CSV keluaran (default alchemy.csv untuk la2, alchemy-<dataset>.csv untuk dataset lain; -csv \"\" untuk melewati)")
+	mapperPath := flag.String("mapper", "", "path mapper ikon JSON keluaran (default mapper2.json untuk la2, mapper-<dataset>.json untuk dataset lain; -mapper \"\" untuk melewati)")
+	datasetName := flag.String("dataset", defaultDatasetName, "label dataset, misalnya la2, la1 atau myths (satu database bisa berisi beberapa dataset)")
+	mythsName := flag.String("myths", "myths", "label dataset untuk resep bertanda (Myths and Monsters); kosongkan untuk membuang resep tersebut")
+	mode := flag.String("mode", modeReplace, "cara menulis database: replace (samakan isi dengan hasil scrape) atau upsert (hanya tambah baris baru)")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Penggunaan: %s [flag] [file.html ...]\n", os.Args[0])
+		flag.PrintDefaults()
 	}
-	defer f.Close()
+	flag.Parse()
 
-	// Buat dokument dari file reader
-	doc, err := goquery.NewDocumentFromReader(f)
-	if err != nil {
-		log.Fatalf("Gagal membuat dokumen dari file: %v", err)
+	inputs = append(inputs, flag.Args()...) // File HTML juga boleh diberikan sebagai argumen
+	if len(inputs) == 0 {
+		inputs = append(inputs, defaultInput)
 	}
-
-	// Buat file CSV untuk gabungan tabel
-	file, err := os.Create("alchemy.csv")
-	if err != nil {
-		log.Printf("Gagal membuat file alchemy.csv: %v", err)
-		return
+	if *datasetName == "" {
+		log.Fatalf("Label dataset tidak boleh kosong")
 	}
-	defer file.Close()
-
-	writer := csv.NewWriter(file)
-	defer writer.Flush()
 
-	// Tulis header CSV
-	writer.Write([]string{"Element", "Item1", "Item2"})
-
-	// Setup SQLite database
-	db, err := sql.Open("sqlite3", "./alchemy.db")
-	if err != nil {
-		log.Fatalf("Gagal membuka SQLite database: %v", err)
+	// Nama CSV dan mapper mengikuti dataset kecuali flag-nya diberikan (termasuk kosong),
+	// supaya scrape dataset lain tidak menimpa file milik la2
+	explicit := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+	defaultCSV, defaultMapper := outputPaths(*datasetName)
+	if !explicit["csv"] {
+		*csvPath = defaultCSV
 	}
-	defer db.Close()
-
-	// Buat tabel dalam database jika belum ada
-	_, err = db.Exec(`
-		CREATE TABLE IF NOT EXISTS elements (
-			element TEXT,
-			item1 TEXT,
-			item2 TEXT
-		);
-	`)
-	if err != nil {
-		log.Fatalf("Gagal membuat tabel: %v", err)
+	if !explicit["mapper"] {
+		*mapperPath = defaultMapper
+	}
+	if *mode != modeReplace && *mode != modeUpsert {
+		log.Fatalf("Mode tidak dikenal: %q (pilih %s atau %s)", *mode, modeReplace, modeUpsert)
 	}
 
-	// Menyimpan data ke database dan CSV
-	doc.Find("h3").Each(func(i int, s *goquery.Selection) {
-		headline := s.Find(".mw-headline")
-		sectionID, exists := headline.Attr("id")
-		if !exists {
-			return
+	// Scrape semua file HTML
+	dataset := Dataset{Images: make(map[string]string)}
+	for _, input := range inputs {
+		parsed, err := scrapeFile(input)
+		if err != nil {
+			log.Fatalf("Gagal men-scrape %s: %v", input, err)
 		}
-
-		table := s.NextAllFiltered("table").First()
-		if table.Length() == 0 {
-			return
+		fmt.Printf("Dibaca: %s (%d baris, %d ikon)\n", input, len(parsed.Records), len(parsed.Images))
+		dataset.Records = append(dataset.Records, parsed.Records...)
+		for element, image := range parsed.Images {
+			dataset.Images[element] = image
 		}
+	}
 
-		// Memasukkan data ke dalam SQLite dan CSV
-		table.Find("tr").Each(func(i int, tr *goquery.Selection) {
-			if i == 0 {
-				return // skip header
-			}
-			tds := tr.Find("td")
-			if tds.Length() < 2 {
-				return
-			}
-
-			element := strings.TrimSpace(tds.Eq(0).Text())
-
-			liFound := false
-			tds.Eq(1).Find("li").Each(func(_ int, li *goquery.Selection) {
-				text := strings.TrimSpace(li.Text())
-				if strings.Contains(text, "+") {
-					// Pisahkan berdasarkan '+'
-					parts := strings.Split(text, "+")
-					if len(parts) != 2 {
-						return
-					}
-					item1 := strings.TrimSpace(parts[0])
-					item2 := strings.TrimSpace(parts[1])
-
-					// Menulis ke file CSV
-					writer.Write([]string{element, item1, item2})
-
-					// Menulis ke SQLite
-					insertDataToSQLite(db, &element, &item1, &item2)
-
-					liFound = true
-				}
-			})
+	// Resep bertanda "(Myths and Monsters)" masuk ke dataset paketnya sendiri
+	if *mythsName == *datasetName {
+		writeOutputs(packDataset(dataset, mythsPack), *datasetName, *mode, *dbPath, *csvPath, *mapperPath)
+		return
+	}
+	writeOutputs(packDataset(dataset, ""), *datasetName, *mode, *dbPath, *csvPath, *mapperPath)
+	if *mythsName != "" && hasPack(dataset, mythsPack) {
+		// Nama file paket selalu mengikuti labelnya, kecuali keluaran tersebut dilewati
+		mythsCSV, mythsMapper := outputPaths(*mythsName)
+		if *csvPath == "" {
+			mythsCSV = ""
+		}
+		if *mapperPath == "" {
+			mythsMapper = ""
+		}
+		writeOutputs(packDataset(dataset, mythsPack), *mythsName, *mode, *dbPath, mythsCSV, mythsMapper)
+	}
+}
 
-			if !liFound {
-				// Tidak ada '+' dalam <li>, tulis satu baris NULL ke CSV dan SQLite
-				writer.Write([]string{element, "", ""})
-				insertDataToSQLite(db, &element, nil, nil)
+// writeOutputs menulis satu dataset ke database, CSV, dan mapper ikon. Path kosong
+// berarti keluaran tersebut dilewati.
+func writeOutputs(dataset Dataset, datasetName string, mode string, dbPath string, csvPath string, mapperPath string) {
+	// Tulis ke SQLite
+	if dbPath != "" {
+		summary, err := writeDatabase(dbPath, dataset, datasetName, mode)
+		if err != nil {
+			log.Fatalf("Gagal menulis database: %v", err)
+		}
+		fmt.Printf("Database %s [%s] (%s): %d ditambah, %d dihapus, %d tidak berubah, %d ikon berubah\n",
+			dbPath, datasetName, mode, summary.Added, summary.Removed, summary.Unchanged, summary.ImagesChanged)
+
+		// Pada mode upsert isi database bisa lebih banyak dari hasil scrape,
+		// jadi CSV dan mapper ditulis dari isi database supaya semuanya tetap sama
+		if mode == modeUpsert {
+			if dataset, err = readDatabase(dbPath, datasetName); err != nil {
+				log.Fatalf("Gagal membaca ulang database: %v", err)
 			}
-		})
+		}
+	}
 
-		fmt.Println("Saved:", sectionID)
-	})
+	// Tulis ke CSV
+	if csvPath != "" {
+		if err := writeCSV(csvPath, dataset.Records); err != nil {
+			log.Fatalf("Gagal menulis %s: %v", csvPath, err)
+		}
+		fmt.Printf("CSV %s: %d baris\n", csvPath, len(dataset.Records))
+	}
 
+	// Tulis mapper ikon, dipakai backend dan frontend untuk menampilkan gambar elemen
+	if mapperPath != "" {
+		if err := writeMapper(mapperPath, dataset.Images); err != nil {
+			log.Fatalf("Gagal menulis %s: %v", mapperPath, err)
+		}
+		fmt.Printf("Mapper %s: %d ikon\n", mapperPath, len(dataset.Images))
+	}
 }
 
-// Fungsi untuk memasukkan data ke SQLite
-func insertDataToSQLite(db *sql.DB, element, item1, item2 *string) {
-
-	// Menyisipkan data ke dalam database SQLite
-	_, err := db.Exec("INSERT INTO elements (element, item1, item2) VALUES (?, ?, ?)", element, item1, item2)
-	if err != nil {
-		log.Printf("Gagal memasukkan data ke SQLite: %v", err)
+// outputPaths mengembalikan nama file CSV dan mapper default untuk dataset. Dataset la2
+// memakai nama yang dibaca backend, dataset lain diberi label dataset di nama filenya.
+func outputPaths(datasetName string) (csvPath string, mapperPath string) {
+	if datasetName == defaultDatasetName {
+		return "alchemy.csv", "mapper2.json"
 	}
+	return "alchemy-" + datasetName + ".csv", "mapper-" + datasetName + ".json"
 }
@@ -0,0 +1,24 @@
+package main
+
+import "testing"
+
+func TestOutputPaths(t *testing.T) {
+	tests := []struct {
+		dataset string
+		csv     string
+		mapper  string
+	}{
+		{dataset: "la2", csv: "alchemy.csv", mapper: "mapper2.json"},
+		{dataset: "la1", csv: "alchemy-la1.csv", mapper: "mapper-la1.json"},
+		{dataset: "myths", csv: "alchemy-myths.csv", mapper: "mapper-myths.json"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dataset, func(t *testing.T) {
+			csv, mapper := outputPaths(tt.dataset)
+			if csv != tt.csv || mapper != tt.mapper {
+				t.Errorf("outputPaths(%q) = %q, %q, want %q, %q", tt.dataset, csv, mapper, tt.csv, tt.mapper)
+			}
+		})
+	}
+}
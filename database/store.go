@@ -0,0 +1,317 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"os"
+
+	_ "github.com/mattn/go-sqlite3" // Untuk SQLite
+)
+
+// Mode penulisan database
+const (
+	modeReplace = "replace" // Isi dataset disamakan dengan hasil scrape
+	modeUpsert  = "upsert"  // Hanya menambah baris yang belum ada
+)
+
+// Label dataset untuk Little Alchemy 2, juga dipakai untuk baris lama tanpa kolom dataset
+const defaultDatasetName = "la2"
+
+// Summary merangkum perubahan isi tabel elements
+type Summary struct {
+	Added         int
+	Removed       int
+	Unchanged     int
+	ImagesChanged int // Ikon baru atau yang URL-nya berubah
+}
+
+// writeDatabase menulis resep ke tabel elements dan ikon ke tabel images dalam satu
+// transaksi. Hanya baris milik dataset datasetName yang disentuh.
+func writeDatabase(path string, dataset Dataset, datasetName string, mode string) (Summary, error) {
+	var summary Summary
+
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return summary, err
+	}
+	defer db.Close()
+
+	tx, err := db.Begin()
+	if err != nil {
+		return summary, err
+	}
+	defer tx.Rollback() // Tidak berpengaruh setelah Commit
+
+	if err := migrateSchema(tx); err != nil {
+		return summary, err
+	}
+
+	existing, err := queryRecords(tx, datasetName)
+	if err != nil {
+		return summary, err
+	}
+	current := make(map[string]bool, len(existing))
+	for _, record := range existing {
+		current[record.key()] = true
+	}
+
+	insert, err := tx.Prepare("INSERT OR IGNORE INTO elements (dataset, element, item1, item2) VALUES (?, ?, ?, ?)")
+	if err != nil {
+		return summary, err
+	}
+	defer insert.Close()
+
+	scraped := make(map[string]bool, len(dataset.Records))
+	for _, record := range dataset.Records {
+		scraped[record.key()] = true
+		if current[record.key()] {
+			summary.Unchanged++
+			continue
+		}
+		if _, err := insert.Exec(datasetName, record.Element, nullable(record.Item1), nullable(record.Item2)); err != nil {
+			return summary, err
+		}
+		summary.Added++
+	}
+
+	if mode == modeReplace {
+		remove, err := tx.Prepare("DELETE FROM elements WHERE dataset = ? AND element = ? AND IFNULL(item1, '') = ? AND IFNULL(item2, '') = ?")
+		if err != nil {
+			return summary, err
+		}
+		defer remove.Close()
+
+		for _, record := range existing {
+			if scraped[record.key()] {
+				continue
+			}
+			if _, err := remove.Exec(datasetName, record.Element, record.Item1, record.Item2); err != nil {
+				return summary, err
+			}
+			summary.Removed++
+		}
+	}
+
+	if summary.ImagesChanged, err = writeImages(tx, dataset.Images, datasetName, mode); err != nil {
+		return summary, err
+	}
+
+	return summary, tx.Commit()
+}
+
+// migrateSchema membuat tabel jika belum ada dan memperbarui database dari versi
+// scraper lama: menambah kolom dataset, membuang duplikat, lalu memasang unique index
+func migrateSchema(tx *sql.Tx) error {
+	_, err := tx.Exec(`
+		CREATE TABLE IF NOT EXISTS elements (
+			element TEXT,
+			item1 TEXT,
+			item2 TEXT,
+			dataset TEXT NOT NULL DEFAULT '` + defaultDatasetName + `'
+		);
+		CREATE TABLE IF NOT EXISTS images (
+			dataset TEXT NOT NULL,
+			element TEXT NOT NULL,
+			image TEXT NOT NULL,
+			PRIMARY KEY (dataset, element)
+		);
+	`)
+	if err != nil {
+		return err
+	}
+
+	hasDataset, err := hasColumn(tx, "elements", "dataset")
+	if err != nil {
+		return err
+	}
+	if !hasDataset {
+		_, err = tx.Exec("ALTER TABLE elements ADD COLUMN dataset TEXT NOT NULL DEFAULT '" + defaultDatasetName + "'")
+		if err != nil {
+			return err
+		}
+	}
+
+	// Tabel images versi lama hanya punya (element, image)
+	hasDataset, err = hasColumn(tx, "images", "dataset")
+	if err != nil {
+		return err
+	}
+	if !hasDataset {
+		_, err = tx.Exec(`
+			ALTER TABLE images RENAME TO images_old;
+			CREATE TABLE images (
+				dataset TEXT NOT NULL,
+				element TEXT NOT NULL,
+				image TEXT NOT NULL,
+				PRIMARY KEY (dataset, element)
+			);
+			INSERT INTO images (dataset, element, image)
+				SELECT '` + defaultDatasetName + `', element, image FROM images_old;
+			DROP TABLE images_old;
+		`)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.Exec(`
+		DELETE FROM elements WHERE rowid NOT IN (
+			SELECT MIN(rowid) FROM elements GROUP BY dataset, element, IFNULL(item1, ''), IFNULL(item2, '')
+		);
+		DROP INDEX IF EXISTS idx_elements_recipe;
+		CREATE UNIQUE INDEX IF NOT EXISTS idx_elements_dataset_recipe
+			ON elements (dataset, element, IFNULL(item1, ''), IFNULL(item2, ''));
+	`)
+	return err
+}
+
+// hasColumn memeriksa apakah tabel sudah punya kolom tertentu
+func hasColumn(q queryer, table, column string) (bool, error) {
+	rows, err := q.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		return false, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return false, err
+		}
+		if name == column {
+			return true, nil
+		}
+	}
+	return false, rows.Err()
+}
+
+// writeImages menulis URL ikon ke tabel images dan mengembalikan jumlah ikon yang berubah
+func writeImages(tx *sql.Tx, images map[string]string, datasetName string, mode string) (int, error) {
+	existing, err := queryImages(tx, datasetName)
+	if err != nil {
+		return 0, err
+	}
+
+	if mode == modeReplace {
+		if _, err := tx.Exec("DELETE FROM images WHERE dataset = ?", datasetName); err != nil {
+			return 0, err
+		}
+	}
+
+	upsert, err := tx.Prepare("INSERT INTO images (dataset, element, image) VALUES (?, ?, ?) ON CONFLICT (dataset, element) DO UPDATE SET image = excluded.image")
+	if err != nil {
+		return 0, err
+	}
+	defer upsert.Close()
+
+	changed := 0
+	for element, image := range images {
+		if existing[element] != image {
+			changed++
+		}
+		if _, err := upsert.Exec(datasetName, element, image); err != nil {
+			return 0, err
+		}
+	}
+	return changed, nil
+}
+
+// readDatabase membaca seluruh isi tabel elements dan images milik satu dataset
+func readDatabase(path string, datasetName string) (Dataset, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return Dataset{}, err
+	}
+	defer db.Close()
+
+	records, err := queryRecords(db, datasetName)
+	if err != nil {
+		return Dataset{}, err
+	}
+	images, err := queryImages(db, datasetName)
+	if err != nil {
+		return Dataset{}, err
+	}
+	return Dataset{Records: records, Images: images}, nil
+}
+
+// queryer dipenuhi oleh *sql.DB maupun *sql.Tx
+type queryer interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// queryRecords membaca isi tabel elements milik satu dataset sesuai urutan penyisipan
+func queryRecords(q queryer, datasetName string) ([]Record, error) {
+	rows, err := q.Query("SELECT element, IFNULL(item1, ''), IFNULL(item2, '') FROM elements WHERE dataset = ? ORDER BY rowid", datasetName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []Record
+	for rows.Next() {
+		var record Record
+		if err := rows.Scan(&record.Element, &record.Item1, &record.Item2); err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+// queryImages membaca isi tabel images milik satu dataset
+func queryImages(q queryer, datasetName string) (map[string]string, error) {
+	rows, err := q.Query("SELECT element, image FROM images WHERE dataset = ?", datasetName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	images := make(map[string]string)
+	for rows.Next() {
+		var element, image string
+		if err := rows.Scan(&element, &image); err != nil {
+			return nil, err
+		}
+		images[element] = image
+	}
+	return images, rows.Err()
+}
+
+// writeCSV menulis record ke file CSV dengan header Element,Item1,Item2
+func writeCSV(path string, records []Record) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	writer.Write([]string{"Element", "Item1", "Item2"})
+	for _, record := range records {
+		writer.Write([]string{record.Element, record.Item1, record.Item2})
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// writeMapper menulis mapper ikon (nama elemen -> URL) seperti mapper2.json
+func writeMapper(path string, images map[string]string) error {
+	content, err := json.MarshalIndent(images, "", "  ") // Kunci map otomatis terurut
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, 0644)
+}
+
+// nullable mengubah string kosong menjadi NULL untuk kolom item elemen dasar
+func nullable(value string) any {
+	if value == "" {
+		return nil
+	}
+	return value
+}
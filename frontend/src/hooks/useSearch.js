@@ -47,8 +47,21 @@ export function useSearch() {
   body: JSON.stringify(requestBody),
 });
 
-      if (!response.ok) throw new Error(`HTTP error! status: ${response.status}`);
-      const data = await response.json();      // Simpan hasil pencarian ke state
+      // Semua response memakai envelope { status, code, message, errors, requestId, data }
+      const body = await response.json().catch(() => null);
+      if (!body) throw new Error(`HTTP error! status: ${response.status}`);
+      if (body.status !== "success") {
+        // Elemen tidak ada: tampilkan saran nama yang mirip dari data.didYouMean
+        const suggestions = (body.data?.didYouMean ?? []).map((s) => s.name);
+        const details = (body.errors ?? []).map((e) => `${e.field} ${e.message}`);
+        let message = body.message;
+        if (suggestions.length > 0) message += `. Maksud Anda: ${suggestions.join(", ")}?`;
+        if (details.length > 0) message += `: ${details.join("; ")}`;
+        const error = new Error(message);
+        error.fromApi = true;
+        throw error;
+      }
+      const data = body.data;                  // Simpan hasil pencarian ke state
       setSearchResults(data.results);
       setNodesVisited(data.nodesVisited);
       setExecutionTime(data.executionTime);
@@ -56,7 +69,7 @@ export function useSearch() {
       setProgress(100); // Progress selesai
     } catch (error) {
       console.error("Search error:", error);
-      alert("Gagal melakukan pencarian. Pastikan backend berjalan.");
+      alert(error.fromApi ? error.message : "Gagal melakukan pencarian. Pastikan backend berjalan.");
     } finally {
       setIsLoading(false); // Selesai loading
     }